@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // BlueprintApi is a string that contains a Blueprint API version identifier.
@@ -17,6 +21,31 @@ const (
 	TestEmpty BlueprintApi = "test/empty"
 )
 
+// IsSupported returns true if a parser is registered for the blueprint API version, see SupportedBlueprintApis.
+func (a BlueprintApi) IsSupported() bool {
+	blueprintParsersMutex.RLock()
+	defer blueprintParsersMutex.RUnlock()
+	_, ok := blueprintParsers[a]
+	return ok
+}
+
+// Validate returns ErrMissingAPIVersion if the blueprint API version is empty and ErrUnsupportedAPIVersion if it is not
+// contained in SupportedBlueprintApis.
+func (a BlueprintApi) Validate() error {
+	if a == "" {
+		return ErrMissingAPIVersion
+	}
+	if !a.IsSupported() {
+		apis := SupportedBlueprintApis()
+		supported := make([]string, 0, len(apis))
+		for _, api := range apis {
+			supported = append(supported, string(api))
+		}
+		return fmt.Errorf("%w %q, supported versions are %s", ErrUnsupportedAPIVersion, a, strings.Join(supported, ", "))
+	}
+	return nil
+}
+
 // GeneralBlueprint defines the minimum set to parse the blueprint API version string in order to select the right
 // blueprint handling strategy. This is necessary in order to accommodate maximal changes in different blueprint API
 // versions.
@@ -26,7 +55,25 @@ type GeneralBlueprint struct {
 	//
 	// This field MUST NOT be MODIFIED or REMOVED because the API is paramount for distinguishing between different
 	// blueprint version implementations.
-	API BlueprintApi `json:"blueprintApi"`
+	API BlueprintApi `json:"blueprintApi" yaml:"blueprintApi"`
+}
+
+// Blueprint is implemented by the blueprints of every blueprint API version, so that they can be handled uniformly.
+// Version-specific fields are available after a type assertion to the concrete type, f. i. *BlueprintV1.
+type Blueprint interface {
+	// GetAPI returns the blueprint API version.
+	GetAPI() BlueprintApi
+	// GetID returns the blueprint ID.
+	GetID() string
+	// GetDogus returns the dogus of the blueprint.
+	GetDogus() []TargetDogu
+	// Validate returns an error if the blueprint violates the invariants of its blueprint API version.
+	Validate() error
+}
+
+// GetAPI returns the blueprint API version. It is promoted to all blueprints embedding the GeneralBlueprint.
+func (g GeneralBlueprint) GetAPI() BlueprintApi {
+	return g.API
 }
 
 // TargetState defines an enum of values that determines a state of installation.
@@ -40,7 +87,8 @@ const (
 	// blueprint was applied.
 	TargetStateAbsent
 	// TargetStateIgnore is currently only internally used to mark items that are present in the CES instance at hand
-	// but not mentioned in the blueprint.
+	// but not mentioned in the blueprint. It is marshalled and parsed like the other states so that such items can be
+	// serialized for debugging, but BlueprintV1.Validate rejects it in blueprints.
 	TargetStateIgnore
 )
 
@@ -52,17 +100,25 @@ func (state TargetState) String() string {
 var toString = map[TargetState]string{
 	TargetStatePresent: "present",
 	TargetStateAbsent:  "absent",
+	TargetStateIgnore:  "ignore",
 }
 
 var toID = map[string]TargetState{
 	"present": TargetStatePresent,
 	"absent":  TargetStateAbsent,
+	"ignore":  TargetStateIgnore,
 }
 
-// MarshalJSON marshals the enum as a quoted json string
+// MarshalJSON marshals the enum as a quoted json string. Values that do not belong to a defined TargetState result in
+// an error.
 func (state TargetState) MarshalJSON() ([]byte, error) {
+	str, ok := toString[state]
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal TargetState %d: unknown target state", int(state))
+	}
+
 	buffer := bytes.NewBufferString(`"`)
-	buffer.WriteString(toString[state])
+	buffer.WriteString(str)
 	buffer.WriteString(`"`)
 	return buffer.Bytes(), nil
 }
@@ -78,11 +134,97 @@ func (state *TargetState) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return fmt.Errorf("cannot unmarshal value %s to a TargetState: %w", string(b), err)
 	}
-	// Note that if the string cannot be found then it will be set to the zero value, 'Created' in this case.
-	*state = toID[j]
+	// a JSON null is treated like an omitted field so the state keeps its default value
+	if string(b) == "null" {
+		return nil
+	}
+
+	id, err := targetStateFromString(j)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal value %s to a TargetState: %w", string(b), err)
+	}
+
+	*state = id
+	return nil
+}
+
+// MarshalYAML marshals the enum as a yaml string. Values that do not belong to a defined TargetState result in an
+// error.
+func (state TargetState) MarshalYAML() (interface{}, error) {
+	str, ok := toString[state]
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal TargetState %d: unknown target state", int(state))
+	}
+
+	return str, nil
+}
+
+// UnmarshalYAML unmarshals a yaml string to the enum value. Use it with usual yaml unmarshalling:
+//
+//	yamlBlob := []byte("present")
+//	var state TargetState
+//	err := yaml.Unmarshal(yamlBlob, &state)
+func (state *TargetState) UnmarshalYAML(value *yaml.Node) error {
+	// a YAML null is treated like an omitted field so the state keeps its default value
+	if value.Tag == "!!null" {
+		return nil
+	}
+
+	var y string
+	err := value.Decode(&y)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal value %s to a TargetState: %w", value.Value, err)
+	}
+
+	id, err := targetStateFromString(y)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal value %s to a TargetState: %w", value.Value, err)
+	}
+
+	*state = id
 	return nil
 }
 
+// ParseTargetState returns the target state for the given string representation, f. i. "absent". Surrounding
+// whitespace and the letter case are ignored. An error is returned for unknown target states.
+func ParseTargetState(str string) (TargetState, error) {
+	return targetStateFromString(str)
+}
+
+// targetStateFromString looks up the target state for the given string. Surrounding whitespace and the letter case
+// are ignored, so that f. i. " Present " is accepted as well.
+func targetStateFromString(str string) (TargetState, error) {
+	id, ok := toID[strings.ToLower(strings.TrimSpace(str))]
+	if !ok {
+		return TargetStatePresent, fmt.Errorf("unknown target state %q, valid target states are %s",
+			str, strings.Join(TargetStateStrings(), ", "))
+	}
+
+	return id, nil
+}
+
+// TargetStates returns all defined target states ordered by their enum value. The returned slice may be modified by
+// the caller. Note that TargetStateIgnore is only used internally and not accepted by the blueprint schema.
+func TargetStates() []TargetState {
+	states := make([]TargetState, 0, len(toID))
+	for _, state := range toID {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+	return states
+}
+
+// TargetStateStrings returns the string representations of all defined target states ordered by their enum value,
+// see TargetStates.
+func TargetStateStrings() []string {
+	states := TargetStates()
+	result := make([]string, 0, len(states))
+	for _, state := range states {
+		result = append(result, toString[state])
+	}
+	return result
+}
+
 // BlueprintV1 describes an abstraction of Cloudogu EcoSystem (CES) parts that should be absent or present within one or
 // more CES instances. When the same Blueprint is applied to two different CES instances it is required to leave two
 // equal instances in terms of the components.
@@ -90,28 +232,235 @@ func (state *TargetState) UnmarshalJSON(b []byte) error {
 // In general additions without changing the version are fine, as long as they don't change semantics. Removal or
 // renaming are breaking changes and require a new blueprint API version.
 type BlueprintV1 struct {
-	GeneralBlueprint
+	GeneralBlueprint `yaml:",inline"`
 	// ID is the unique name of the set over all parts. This blueprint ID should be used to distinguish from similar
 	// blueprints between humans in an easy way. Must not be empty.
-	ID string `json:"blueprintId"`
+	ID string `json:"blueprintId" yaml:"blueprintId"`
 	// CesAppVersion defines the exact version of the cesapp that should be present in the CES instance after which this
 	// blueprint was applied. Must not be empty.
 	//
 	// This field MUST NOT be MODIFIED or REMOVED because the cesapp is paramount for interpreting blueprint
 	// implementations.
-	CesAppVersion string `json:"cesappVersion"`
+	CesAppVersion string `json:"cesappVersion" yaml:"cesappVersion"`
 	// Dogus contains a set of exact dogu versions which should be present or absent in the CES instance after which this
-	// blueprint was applied. Optional.
-	Dogus []TargetDogu `json:"dogus,omitempty"`
+	// blueprint was applied. Present dogus must not share their simple name, f. i. "official/nginx" and "premium/nginx"
+	// cannot be installed at the same time. Optional.
+	Dogus []TargetDogu `json:"dogus,omitempty" yaml:"dogus,omitempty"`
 	// Packages contains a set of exact package versions which should be present or absent in the CES instance after which
 	// this blueprint was applied. The packages must correspond to the used operating system package manager. Optional.
-	Packages []TargetPackage `json:"packages,omitempty"`
+	Packages []TargetPackage `json:"packages,omitempty" yaml:"packages,omitempty"`
 	// Used to configure registry globalRegistryEntries on blueprint upgrades
-	RegistryConfig RegistryConfig `json:"registryConfig,omitempty"`
+	RegistryConfig RegistryConfig `json:"registryConfig,omitempty" yaml:"registryConfig,omitempty"`
 	// Used to remove registry globalRegistryEntries on blueprint upgrades
-	RegistryConfigAbsent []string `json:"registryConfigAbsent,omitempty"`
+	RegistryConfigAbsent []string `json:"registryConfigAbsent,omitempty" yaml:"registryConfigAbsent,omitempty"`
 	// Used to configure encrypted registry globalRegistryEntries on blueprint upgrades
-	RegistryConfigEncrypted RegistryConfig `json:"registryConfigEncrypted,omitempty"`
+	RegistryConfigEncrypted EncryptedRegistryConfig `json:"registryConfigEncrypted,omitempty" yaml:"registryConfigEncrypted,omitempty"`
+	// Metadata contains free-form annotations of the blueprint, f. i. its author or a ticket link. It is not interpreted
+	// when the blueprint is applied. Optional.
+	Metadata map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// FindDogu returns the dogu with the given full namespaced name, f. i. "official/nginx". The returned bool is false if
+// the blueprint does not contain such a dogu.
+func (b BlueprintV1) FindDogu(name string) (TargetDogu, bool) {
+	for _, dogu := range b.Dogus {
+		if dogu.Name == name {
+			return dogu, true
+		}
+	}
+	return TargetDogu{}, false
+}
+
+// FindPackage returns the package with the given name. The returned bool is false if the blueprint does not contain
+// such a package. The package manager is not taken into account: if packages of several package managers share the
+// name, the first of them is returned. Use PackagesForManager to look up the package of a specific package manager.
+func (b BlueprintV1) FindPackage(name string) (TargetPackage, bool) {
+	for _, pkg := range b.Packages {
+		if pkg.Name == name {
+			return pkg, true
+		}
+	}
+	return TargetPackage{}, false
+}
+
+// DogusByState returns the dogus of the blueprint with the given target state in their original order. Dogus without
+// an explicit target state are considered to be present.
+func (b BlueprintV1) DogusByState(state TargetState) []TargetDogu {
+	var result []TargetDogu
+	for _, dogu := range b.Dogus {
+		if dogu.TargetState == state {
+			result = append(result, dogu)
+		}
+	}
+	return result
+}
+
+// GetID returns the blueprint ID.
+func (b BlueprintV1) GetID() string {
+	return b.ID
+}
+
+// GetDogus returns the dogus of the blueprint.
+func (b BlueprintV1) GetDogus() []TargetDogu {
+	return b.Dogus
+}
+
+// PackagesByState returns the packages of the blueprint with the given target state in their original order. Packages
+// without an explicit target state are considered to be present.
+func (b BlueprintV1) PackagesByState(state TargetState) []TargetPackage {
+	var result []TargetPackage
+	for _, pkg := range b.Packages {
+		if pkg.TargetState == state {
+			result = append(result, pkg)
+		}
+	}
+	return result
+}
+
+// PackagesForManager returns the packages of the blueprint which belong to the given package manager, f. i. "apt", in
+// their original order. Packages without a package manager are returned for every package manager.
+func (b BlueprintV1) PackagesForManager(packageManager string) []TargetPackage {
+	var result []TargetPackage
+	for _, pkg := range b.Packages {
+		if pkg.PackageManager == "" || pkg.PackageManager == packageManager {
+			result = append(result, pkg)
+		}
+	}
+	return result
+}
+
+// DoguNamespaces returns the sorted and de-duplicated namespaces of all dogus of the blueprint, f. i. "official" and
+// "premium". An error is returned if the name of a dogu does not contain a namespace.
+func (b BlueprintV1) DoguNamespaces() ([]string, error) {
+	var namespaces []string
+	for i, dogu := range b.Dogus {
+		namespace, _, err := dogu.SplitName()
+		if err != nil {
+			return nil, fmt.Errorf("could not determine namespace of dogu at index %d: %w", i, err)
+		}
+		namespaces = append(namespaces, namespace)
+	}
+
+	sort.Strings(namespaces)
+	return dedupe(namespaces), nil
+}
+
+// String returns a concise summary of the blueprint containing its ID, API version, cesapp version, the number of dogus
+// and packages per target state and the number of registry config keys. No registry config values are shown.
+func (b BlueprintV1) String() string {
+	doguStates := make([]TargetState, 0, len(b.Dogus))
+	for _, dogu := range b.Dogus {
+		doguStates = append(doguStates, dogu.TargetState)
+	}
+	packageStates := make([]TargetState, 0, len(b.Packages))
+	for _, pkg := range b.Packages {
+		packageStates = append(packageStates, pkg.TargetState)
+	}
+
+	return fmt.Sprintf("blueprint %q (API %s, cesapp %s): dogus %s, packages %s, registry config keys: %d, absent registry config keys: %d, encrypted registry config keys: %d",
+		b.ID, b.API, b.CesAppVersion, countTargetStates(doguStates), countTargetStates(packageStates),
+		len(b.RegistryConfig.Flatten()), len(b.RegistryConfigAbsent), len(RegistryConfig(b.RegistryConfigEncrypted).Flatten()))
+}
+
+// countTargetStates returns a summary like "3 (present: 2, absent: 1)" of the given target states.
+func countTargetStates(states []TargetState) string {
+	counts := map[TargetState]int{}
+	for _, state := range states {
+		counts[state]++
+	}
+
+	var parts []string
+	for _, stateString := range TargetStateStrings() {
+		state := toID[stateString]
+		if counts[state] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", stateString, counts[state]))
+		}
+		delete(counts, state)
+	}
+	undefinedStates := make([]TargetState, 0, len(counts))
+	for state := range counts {
+		undefinedStates = append(undefinedStates, state)
+	}
+	sort.Slice(undefinedStates, func(i, j int) bool { return undefinedStates[i] < undefinedStates[j] })
+	for _, state := range undefinedStates {
+		parts = append(parts, fmt.Sprintf("%d: %d", int(state), counts[state]))
+	}
+
+	if len(parts) == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d (%s)", len(states), strings.Join(parts, ", "))
+}
+
+// DeepCopy returns a copy of the blueprint that shares no slices or maps with the original, so that the copy can be
+// modified without affecting the original.
+func (b BlueprintV1) DeepCopy() BlueprintV1 {
+	result := b
+	if b.Dogus != nil {
+		result.Dogus = make([]TargetDogu, len(b.Dogus))
+		copy(result.Dogus, b.Dogus)
+	}
+	if b.Packages != nil {
+		result.Packages = make([]TargetPackage, len(b.Packages))
+		copy(result.Packages, b.Packages)
+	}
+	if b.RegistryConfigAbsent != nil {
+		result.RegistryConfigAbsent = make([]string, len(b.RegistryConfigAbsent))
+		copy(result.RegistryConfigAbsent, b.RegistryConfigAbsent)
+	}
+	result.RegistryConfig = b.RegistryConfig.DeepCopy()
+	result.RegistryConfigEncrypted = b.RegistryConfigEncrypted.DeepCopy()
+	if b.Metadata != nil {
+		result.Metadata = make(map[string]string, len(b.Metadata))
+		for key, value := range b.Metadata {
+			result.Metadata[key] = value
+		}
+	}
+	return result
+}
+
+// Equal returns true if both blueprints are semantically equal. The order of dogus, packages and absent registry
+// config entries is not taken into account and identical entries are only taken into account once. Registry configs
+// are compared deeply, whereby empty and missing sections are considered equal. Metadata and comments are ignored
+// because they are not interpreted when applying a blueprint.
+func (b BlueprintV1) Equal(other BlueprintV1) bool {
+	if b.API != other.API || b.ID != other.ID || b.CesAppVersion != other.CesAppVersion {
+		return false
+	}
+
+	dogus, otherDogus := dedupeFunc(sortedDogus(b.Dogus), TargetDogu.Equal), dedupeFunc(sortedDogus(other.Dogus), TargetDogu.Equal)
+	if len(dogus) != len(otherDogus) {
+		return false
+	}
+	for i := range dogus {
+		if !dogus[i].Equal(otherDogus[i]) {
+			return false
+		}
+	}
+
+	packages, otherPackages := dedupeFunc(sortedPackages(b.Packages), TargetPackage.Equal),
+		dedupeFunc(sortedPackages(other.Packages), TargetPackage.Equal)
+	if len(packages) != len(otherPackages) {
+		return false
+	}
+	for i := range packages {
+		if !packages[i].Equal(otherPackages[i]) {
+			return false
+		}
+	}
+
+	absent, otherAbsent := dedupe(sortedStrings(b.RegistryConfigAbsent)), dedupe(sortedStrings(other.RegistryConfigAbsent))
+	if len(absent) != len(otherAbsent) {
+		return false
+	}
+	for i := range absent {
+		if absent[i] != otherAbsent[i] {
+			return false
+		}
+	}
+
+	return reflect.DeepEqual(b.RegistryConfig.Flatten(), other.RegistryConfig.Flatten()) &&
+		reflect.DeepEqual(RegistryConfig(b.RegistryConfigEncrypted).Flatten(), RegistryConfig(other.RegistryConfigEncrypted).Flatten())
 }
 
 type RegistryConfig map[string]map[string]interface{}
@@ -120,24 +469,41 @@ type RegistryConfig map[string]map[string]interface{}
 // was applied.
 type TargetDogu struct {
 	// Name defines the name of the dogu including its namespace, f. i. "official/nginx". Must not be empty.
-	Name string `json:"name"`
+	Name string `json:"name" yaml:"name"`
 	// Version defines the version of the dogu that is to be installed. Must not be empty if the targetState is "present";
 	// otherwise it is optional and is not going to be interpreted.
-	Version string `json:"version"`
-	// TargetState defines a state of installation of this dogu. Optional field, but defaults to "TargetStatePresent"
-	TargetState TargetState `json:"targetState"`
+	Version string `json:"version" yaml:"version"`
+	// TargetState defines a state of installation of this dogu. Optional field, but defaults to "TargetStatePresent".
+	// The default is omitted when the dogu is marshalled. Because TargetStatePresent is the zero value, encoding/json
+	// resolves an omitted target state and an explicit "present" identically, so they cannot be told apart after
+	// parsing.
+	TargetState TargetState `json:"targetState" yaml:"targetState,omitempty"`
+	// Comment contains a free-form note about this dogu, f. i. the reason for its version. It is not interpreted when
+	// the blueprint is applied. Optional.
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
 }
 
 // TargetPackage an operating system package, its version, and the installation state in which it is supposed to be
 // after a blueprint was applied.
 type TargetPackage struct {
 	// Name defines the name of the package. Must not be empty.
-	Name string `json:"name"`
+	Name string `json:"name" yaml:"name"`
 	// Version defines the version of the package that is to be installed. Must not be empty if the targetState is
-	// "present"; otherwise it is optional and is not going to be interpreted.
-	Version string `json:"version"`
-	// TargetState defines a state of installation of this package. Optional field, but defaults to "TargetStatePresent"
-	TargetState TargetState `json:"targetState"`
+	// "present"; otherwise it is optional and is not going to be interpreted. Besides an exact version the version may
+	// contain a constraint like ">=1.2.0", see TargetPackage.Matches.
+	Version string `json:"version" yaml:"version"`
+	// TargetState defines a state of installation of this package. Optional field, but defaults to
+	// "TargetStatePresent". The default is omitted when the package is marshalled. Because TargetStatePresent is the
+	// zero value, encoding/json resolves an omitted target state and an explicit "present" identically, so they cannot
+	// be told apart after parsing.
+	TargetState TargetState `json:"targetState" yaml:"targetState,omitempty"`
+	// PackageManager defines the operating system package manager the package belongs to, f. i. "apt" or "yum", so
+	// that blueprints can target CES instances on different operating systems. A package without a package manager
+	// applies to every package manager. Packages of different package managers may share their name. Optional.
+	PackageManager string `json:"packageManager,omitempty" yaml:"packageManager,omitempty"`
+	// Comment contains a free-form note about this package, f. i. the reason for its version. It is not interpreted
+	// when the blueprint is applied. Optional.
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
 }
 
 // ParseBlueprint parses a given byte slice to a GeneralBlueprint so the blueprint version can be determined.
@@ -145,7 +511,7 @@ func ParseBlueprint(rawBlueprint []byte) (GeneralBlueprint, error) {
 	var preparsedBlueprint GeneralBlueprint
 	err := json.Unmarshal(rawBlueprint, &preparsedBlueprint)
 	if err != nil {
-		return GeneralBlueprint{}, errors.Wrap(err, "could not parse blueprint. Please check the blueprint for validity")
+		return GeneralBlueprint{}, fmt.Errorf("could not parse blueprint. Please check the blueprint for validity: %w: %w", ErrInvalidJSON, err)
 	}
 
 	return preparsedBlueprint, nil
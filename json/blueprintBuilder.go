@@ -0,0 +1,76 @@
+package json
+
+// BlueprintBuilder constructs a BlueprintV1 with chainable methods. Use NewBlueprintBuilder to create one.
+type BlueprintBuilder struct {
+	blueprint BlueprintV1
+}
+
+// NewBlueprintBuilder creates a builder for a blueprint with the API version V1.
+func NewBlueprintBuilder() *BlueprintBuilder {
+	return &BlueprintBuilder{blueprint: BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: V1}}}
+}
+
+// WithID sets the ID of the blueprint.
+func (bb *BlueprintBuilder) WithID(id string) *BlueprintBuilder {
+	bb.blueprint.ID = id
+	return bb
+}
+
+// WithCesAppVersion sets the cesapp version of the blueprint.
+func (bb *BlueprintBuilder) WithCesAppVersion(version string) *BlueprintBuilder {
+	bb.blueprint.CesAppVersion = version
+	return bb
+}
+
+// AddDogu adds a dogu with the given full namespaced name, version and target state to the blueprint.
+func (bb *BlueprintBuilder) AddDogu(name string, version string, state TargetState) *BlueprintBuilder {
+	bb.blueprint.Dogus = append(bb.blueprint.Dogus, TargetDogu{Name: name, Version: version, TargetState: state})
+	return bb
+}
+
+// AddPackage adds a package with the given name, version and target state to the blueprint.
+func (bb *BlueprintBuilder) AddPackage(name string, version string, state TargetState) *BlueprintBuilder {
+	bb.blueprint.Packages = append(bb.blueprint.Packages, TargetPackage{Name: name, Version: version, TargetState: state})
+	return bb
+}
+
+// SetRegistryConfig sets the value of the given registry config key in the given section.
+func (bb *BlueprintBuilder) SetRegistryConfig(section string, key string, value interface{}) *BlueprintBuilder {
+	bb.blueprint.RegistryConfig = setRegistryConfigValue(bb.blueprint.RegistryConfig, section, key, value)
+	return bb
+}
+
+// SetRegistryConfigEncrypted sets the value of the given encrypted registry config key in the given section.
+func (bb *BlueprintBuilder) SetRegistryConfigEncrypted(section string, key string, value interface{}) *BlueprintBuilder {
+	config := setRegistryConfigValue(RegistryConfig(bb.blueprint.RegistryConfigEncrypted), section, key, value)
+	bb.blueprint.RegistryConfigEncrypted = EncryptedRegistryConfig(config)
+	return bb
+}
+
+// AddRegistryConfigAbsent adds a registry key path, f. i. "_global/fqdn", which is to be removed.
+func (bb *BlueprintBuilder) AddRegistryConfigAbsent(keyPath string) *BlueprintBuilder {
+	bb.blueprint.RegistryConfigAbsent = append(bb.blueprint.RegistryConfigAbsent, keyPath)
+	return bb
+}
+
+// Build validates and returns the blueprint. The returned blueprint shares no slices or maps with the builder, so the
+// builder can be modified further without affecting it.
+func (bb *BlueprintBuilder) Build() (BlueprintV1, error) {
+	err := bb.blueprint.Validate()
+	if err != nil {
+		return BlueprintV1{}, err
+	}
+
+	return bb.blueprint.DeepCopy(), nil
+}
+
+func setRegistryConfigValue(config RegistryConfig, section string, key string, value interface{}) RegistryConfig {
+	if config == nil {
+		config = RegistryConfig{}
+	}
+	if config[section] == nil {
+		config[section] = map[string]interface{}{}
+	}
+	config[section][key] = value
+	return config
+}
@@ -0,0 +1,68 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprintBuilder_Build(t *testing.T) {
+	t.Run("should build valid blueprint", func(t *testing.T) {
+		actual, err := NewBlueprintBuilder().
+			WithID("my-blueprint").
+			WithCesAppVersion("7.0.0-1").
+			AddDogu("official/nginx", "1.2.3-4", TargetStatePresent).
+			AddDogu("official/redmine", "", TargetStateAbsent).
+			AddPackage("cesapp", "7.0.0-1", TargetStatePresent).
+			SetRegistryConfig("_global", "fqdn", "ces.example.com").
+			SetRegistryConfig("_global", "admin_group", "admins").
+			SetRegistryConfigEncrypted("redmine", "secret", "s3cr3t").
+			AddRegistryConfigAbsent("redmine/theme").
+			Build()
+
+		require.NoError(t, err)
+		expected := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStatePresent},
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+			},
+			Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1", TargetState: TargetStatePresent}},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com", "admin_group": "admins"}},
+			RegistryConfigAbsent:    []string{"redmine/theme"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+		}
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("should fail for invalid blueprint", func(t *testing.T) {
+		actual, err := NewBlueprintBuilder().
+			WithCesAppVersion("7.0.0-1").
+			AddDogu("nginx", "1.2.3-4", TargetStatePresent).
+			Build()
+
+		require.Error(t, err)
+		assert.Equal(t, BlueprintV1{}, actual)
+		assert.ErrorContains(t, err, "blueprint ID must not be empty")
+		assert.ErrorContains(t, err, `dogu name "nginx" must consist of a namespace and a name`)
+	})
+	t.Run("should not share state with built blueprints", func(t *testing.T) {
+		sut := NewBlueprintBuilder().
+			WithID("my-blueprint").
+			WithCesAppVersion("7.0.0-1").
+			SetRegistryConfig("_global", "fqdn", "ces.example.com")
+		first, err := sut.Build()
+		require.NoError(t, err)
+
+		sut.SetRegistryConfig("_global", "fqdn", "other.example.com").AddDogu("official/nginx", "1.2.3-4", TargetStatePresent)
+		second, err := sut.Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, "ces.example.com", first.RegistryConfig["_global"]["fqdn"])
+		assert.Empty(t, first.Dogus)
+		assert.Equal(t, "other.example.com", second.RegistryConfig["_global"]["fqdn"])
+		assert.Len(t, second.Dogus, 1)
+	})
+}
@@ -0,0 +1,48 @@
+package json
+
+import (
+	"fmt"
+
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+// CompareCesAppVersions compares two cesapp versions like "7.0.0-1". It returns -1 if a is older than b, 0 if both are
+// equal and 1 if a is newer than b. An error is returned if either version is malformed.
+func CompareCesAppVersions(a, b string) (int, error) {
+	versionA, err := parseCesAppVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	versionB, err := parseCesAppVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case versionA.IsOlderThan(versionB):
+		return -1, nil
+	case versionA.IsNewerThan(versionB):
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// IsCesAppUpgradeFrom returns true if the cesapp version of the blueprint is newer than the one of the other blueprint.
+// Equal versions are no upgrade. An error is returned if either cesapp version is malformed, so that a downgrade is not
+// mistaken for an upgrade.
+func (b BlueprintV1) IsCesAppUpgradeFrom(other BlueprintV1) (bool, error) {
+	comparison, err := CompareCesAppVersions(b.CesAppVersion, other.CesAppVersion)
+	if err != nil {
+		return false, err
+	}
+	return comparison > 0, nil
+}
+
+func parseCesAppVersion(version string) (core.Version, error) {
+	parsed, err := parseVersion(version)
+	if err != nil {
+		return core.Version{}, fmt.Errorf("invalid cesapp version %q: %w", version, err)
+	}
+	return parsed, nil
+}
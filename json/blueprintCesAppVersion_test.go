@@ -0,0 +1,70 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareCesAppVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{"older", "7.0.0-1", "7.1.0-1", -1},
+		{"older extension", "7.0.0-1", "7.0.0-2", -1},
+		{"equal", "7.0.0-1", "7.0.0-1", 0},
+		{"newer", "7.10.0-1", "7.9.0-1", 1},
+		{"newer major", "8.0.0-1", "7.99.99-99", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := CompareCesAppVersions(tt.a, tt.b)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+
+	t.Run("should fail for malformed versions", func(t *testing.T) {
+		for _, version := range []string{"", "seven", " 7.0.0-1", ">=7.0.0-1", "1.2.3.4-5"} {
+			_, err := CompareCesAppVersions("7.0.0-1", version)
+			assert.ErrorContains(t, err, "invalid cesapp version")
+
+			_, err = CompareCesAppVersions(version, "7.0.0-1")
+			assert.ErrorContains(t, err, "invalid cesapp version")
+		}
+	})
+}
+
+func TestBlueprintV1_IsCesAppUpgradeFrom(t *testing.T) {
+	current := BlueprintV1{CesAppVersion: "7.0.0-1"}
+
+	t.Run("should detect upgrade", func(t *testing.T) {
+		actual, err := BlueprintV1{CesAppVersion: "7.1.0-1"}.IsCesAppUpgradeFrom(current)
+
+		require.NoError(t, err)
+		assert.True(t, actual)
+	})
+	t.Run("should not consider equal or older versions an upgrade", func(t *testing.T) {
+		for _, version := range []string{"7.0.0-1", "6.9.0-1"} {
+			actual, err := BlueprintV1{CesAppVersion: version}.IsCesAppUpgradeFrom(current)
+
+			require.NoError(t, err)
+			assert.False(t, actual, version)
+		}
+	})
+	t.Run("should fail for version with nano part", func(t *testing.T) {
+		_, err := BlueprintV1{CesAppVersion: "7.0.0.1-1"}.IsCesAppUpgradeFrom(current)
+
+		assert.ErrorContains(t, err, `invalid cesapp version "7.0.0.1-1": version must consist of exactly three numeric parts`)
+	})
+	t.Run("should fail for missing version", func(t *testing.T) {
+		_, err := BlueprintV1{CesAppVersion: "7.1.0-1"}.IsCesAppUpgradeFrom(BlueprintV1{})
+
+		assert.ErrorContains(t, err, `invalid cesapp version "": version must not be empty`)
+	})
+}
@@ -0,0 +1,185 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type decoderState int
+
+const (
+	decoderStateStart decoderState = iota
+	decoderStateFields
+	decoderStateDogus
+	decoderStateDone
+)
+
+// BlueprintHeader contains the fields of a blueprint which identify it.
+type BlueprintHeader struct {
+	// API is the blueprint API version of the blueprint.
+	API BlueprintApi
+	// ID is the unique name of the blueprint.
+	ID string
+	// CesAppVersion is the cesapp version of the blueprint.
+	CesAppVersion string
+}
+
+// BlueprintDecoder reads a blueprint from a stream without loading it into memory as a whole. It reads the header of
+// the blueprint and then decodes the dogus one at a time. All other fields of the blueprint are skipped.
+type BlueprintDecoder struct {
+	decoder *json.Decoder
+	header  BlueprintHeader
+	state   decoderState
+}
+
+// NewBlueprintDecoder creates a BlueprintDecoder that reads from the given reader.
+func NewBlueprintDecoder(r io.Reader) *BlueprintDecoder {
+	return &BlueprintDecoder{decoder: json.NewDecoder(r)}
+}
+
+// ReadHeader reads the blueprint up to its dogus and returns the header fields found so far. Header fields after the
+// dogus are available from Header once NextDogu returned io.EOF.
+func (d *BlueprintDecoder) ReadHeader() (BlueprintHeader, error) {
+	if d.state == decoderStateStart {
+		err := d.readObjectStart()
+		if err != nil {
+			return BlueprintHeader{}, err
+		}
+		err = d.readFields()
+		if err != nil {
+			return BlueprintHeader{}, err
+		}
+	}
+
+	return d.header, nil
+}
+
+// Header returns the header fields read so far.
+func (d *BlueprintDecoder) Header() BlueprintHeader {
+	return d.header
+}
+
+// NextDogu decodes the next dogu of the blueprint. It reads the header first if ReadHeader was not called before.
+// io.EOF is returned after the last dogu.
+func (d *BlueprintDecoder) NextDogu() (TargetDogu, error) {
+	_, err := d.ReadHeader()
+	if err != nil {
+		return TargetDogu{}, err
+	}
+
+	for d.state == decoderStateDogus {
+		if d.decoder.More() {
+			var dogu TargetDogu
+			err = d.decoder.Decode(&dogu)
+			if err != nil {
+				return TargetDogu{}, d.invalidJSONError(err)
+			}
+			return dogu, nil
+		}
+
+		// consume the closing bracket of the dogu array and continue with the remaining fields
+		_, err = d.decoder.Token()
+		if err != nil {
+			return TargetDogu{}, d.invalidJSONError(err)
+		}
+		d.state = decoderStateFields
+		err = d.readFields()
+		if err != nil {
+			return TargetDogu{}, err
+		}
+	}
+
+	return TargetDogu{}, io.EOF
+}
+
+func (d *BlueprintDecoder) readObjectStart() error {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return d.invalidJSONError(err)
+	}
+	if token != json.Delim('{') {
+		return d.invalidJSONError(fmt.Errorf("expected blueprint object but found %v", token))
+	}
+
+	d.state = decoderStateFields
+	return nil
+}
+
+// readFields reads the fields of the blueprint object until it reaches the start of the dogu array or the end of the
+// object.
+func (d *BlueprintDecoder) readFields() error {
+	for d.decoder.More() {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return d.invalidJSONError(err)
+		}
+
+		switch token {
+		case "blueprintApi":
+			err = d.decoder.Decode(&d.header.API)
+		case "blueprintId":
+			err = d.decoder.Decode(&d.header.ID)
+		case "cesappVersion":
+			err = d.decoder.Decode(&d.header.CesAppVersion)
+		case "dogus":
+			return d.readDoguArrayStart()
+		default:
+			err = d.skipValue()
+		}
+		if err != nil {
+			return d.invalidJSONError(err)
+		}
+	}
+
+	// consume the closing brace of the blueprint object
+	_, err := d.decoder.Token()
+	if err != nil {
+		return d.invalidJSONError(err)
+	}
+	d.state = decoderStateDone
+	return nil
+}
+
+func (d *BlueprintDecoder) readDoguArrayStart() error {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return d.invalidJSONError(err)
+	}
+
+	switch token {
+	case json.Delim('['):
+		d.state = decoderStateDogus
+		return nil
+	case nil:
+		// a null dogu array contains no dogus
+		return d.readFields()
+	default:
+		return d.invalidJSONError(fmt.Errorf("expected dogu array but found %v", token))
+	}
+}
+
+// skipValue skips the next JSON value token by token so that it is not held in memory.
+func (d *BlueprintDecoder) skipValue() error {
+	depth := 0
+	for {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (d *BlueprintDecoder) invalidJSONError(err error) error {
+	return fmt.Errorf("could not decode blueprint at offset %d: %w: %w", d.decoder.InputOffset(), ErrInvalidJSON, err)
+}
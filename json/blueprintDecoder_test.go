@@ -0,0 +1,108 @@
+package json
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAllDogus(t *testing.T, sut *BlueprintDecoder) []TargetDogu {
+	t.Helper()
+
+	var dogus []TargetDogu
+	for {
+		dogu, err := sut.NextDogu()
+		if err == io.EOF {
+			return dogus
+		}
+		require.NoError(t, err)
+		dogus = append(dogus, dogu)
+	}
+}
+
+func TestBlueprintDecoder(t *testing.T) {
+	t.Run("should read header and iterate dogus", func(t *testing.T) {
+		sut := NewBlueprintDecoder(strings.NewReader(`{
+			"blueprintApi": "v1",
+			"blueprintId": "my-blueprint",
+			"registryConfig": {"_global": {"fqdn": "ces.example.com", "nested": [{"a": [1, 2]}]}},
+			"cesappVersion": "7.0.0-1",
+			"dogus": [
+				{"name": "official/nginx", "version": "1.2.3-4"},
+				{"name": "official/redmine", "targetState": "absent"}
+			],
+			"packages": [{"name": "cesapp", "version": "7.0.0-1"}]
+		}`))
+
+		header, err := sut.ReadHeader()
+		require.NoError(t, err)
+		assert.Equal(t, BlueprintHeader{API: V1, ID: "my-blueprint", CesAppVersion: "7.0.0-1"}, header)
+
+		dogus := readAllDogus(t, sut)
+		assert.Equal(t, []TargetDogu{
+			{Name: "official/nginx", Version: "1.2.3-4"},
+			{Name: "official/redmine", TargetState: TargetStateAbsent},
+		}, dogus)
+
+		_, err = sut.NextDogu()
+		assert.Equal(t, io.EOF, err)
+	})
+	t.Run("should read header fields after dogus", func(t *testing.T) {
+		sut := NewBlueprintDecoder(strings.NewReader(`{
+			"blueprintApi": "v1",
+			"dogus": [{"name": "official/nginx", "version": "1.2.3-4"}],
+			"blueprintId": "my-blueprint",
+			"cesappVersion": "7.0.0-1"
+		}`))
+
+		header, err := sut.ReadHeader()
+		require.NoError(t, err)
+		assert.Equal(t, BlueprintHeader{API: V1}, header)
+
+		dogus := readAllDogus(t, sut)
+		assert.Len(t, dogus, 1)
+		assert.Equal(t, BlueprintHeader{API: V1, ID: "my-blueprint", CesAppVersion: "7.0.0-1"}, sut.Header())
+	})
+	t.Run("should handle blueprint without dogus", func(t *testing.T) {
+		sut := NewBlueprintDecoder(strings.NewReader(`{"blueprintApi": "v1", "dogus": null, "blueprintId": "my-blueprint"}`))
+
+		dogus := readAllDogus(t, sut)
+
+		assert.Empty(t, dogus)
+		assert.Equal(t, BlueprintHeader{API: V1, ID: "my-blueprint"}, sut.Header())
+	})
+	t.Run("should fail for non-object blueprint", func(t *testing.T) {
+		sut := NewBlueprintDecoder(strings.NewReader(`[]`))
+
+		_, err := sut.ReadHeader()
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "expected blueprint object")
+	})
+	t.Run("should fail for invalid dogu", func(t *testing.T) {
+		sut := NewBlueprintDecoder(strings.NewReader(`{"dogus": [{"name": "official/nginx", "targetState": "removed"}]}`))
+
+		_, err := sut.NextDogu()
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, `unknown target state "removed"`)
+	})
+	t.Run("should fail for invalid dogu array", func(t *testing.T) {
+		sut := NewBlueprintDecoder(strings.NewReader(`{"dogus": {}}`))
+
+		_, err := sut.NextDogu()
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "expected dogu array")
+	})
+	t.Run("should fail for truncated input", func(t *testing.T) {
+		sut := NewBlueprintDecoder(strings.NewReader(`{"blueprintApi": "v1", "packages": [{"name": `))
+
+		_, err := sut.ReadHeader()
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+	})
+}
@@ -0,0 +1,189 @@
+package json
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ChangeType classifies a single change between two blueprints.
+type ChangeType string
+
+const (
+	// ChangeTypeAdded marks an item that is only contained in the new blueprint.
+	ChangeTypeAdded ChangeType = "added"
+	// ChangeTypeRemoved marks an item that is only contained in the old blueprint.
+	ChangeTypeRemoved ChangeType = "removed"
+	// ChangeTypeVersionChanged marks a dogu or package whose version differs between both blueprints.
+	ChangeTypeVersionChanged ChangeType = "versionChanged"
+	// ChangeTypeTargetStateChanged marks a dogu or package whose target state differs between both blueprints.
+	ChangeTypeTargetStateChanged ChangeType = "targetStateChanged"
+	// ChangeTypeValueChanged marks a registry config key whose value differs between both blueprints.
+	ChangeTypeValueChanged ChangeType = "valueChanged"
+)
+
+// BlueprintDiff contains the structured changeset between two blueprints. All entries are sorted by name or key path.
+type BlueprintDiff struct {
+	// Dogus contains an entry for every dogu that differs between both blueprints.
+	Dogus []DoguDiff
+	// Packages contains an entry for every package that differs between both blueprints.
+	Packages []PackageDiff
+	// RegistryConfig contains an entry for every registry config key that differs between both blueprints.
+	RegistryConfig []RegistryConfigDiff
+	// RegistryConfigEncrypted contains an entry for every encrypted registry config key that differs between both
+	// blueprints. The values are redacted.
+	RegistryConfigEncrypted []RegistryConfigDiff
+}
+
+// DoguDiff describes the changes of a single dogu. Old is the zero value if the dogu was added, New is the zero value
+// if the dogu was removed.
+type DoguDiff struct {
+	Name    string
+	Old     TargetDogu
+	New     TargetDogu
+	Changes []ChangeType
+}
+
+// PackageDiff describes the changes of a single package. Old is the zero value if the package was added, New is the
+// zero value if the package was removed. Packages are matched by their name and package manager, so Name is prefixed
+// with the package manager if the package has one, f. i. "apt:curl".
+type PackageDiff struct {
+	Name    string
+	Old     TargetPackage
+	New     TargetPackage
+	Changes []ChangeType
+}
+
+// RegistryConfigDiff describes the change of a single registry config key. The key is given as a flattened key path
+// in the form "section/key".
+type RegistryConfigDiff struct {
+	Key      string
+	OldValue interface{}
+	NewValue interface{}
+	Change   ChangeType
+}
+
+// IsEmpty returns true if the diff contains no changes at all.
+func (d BlueprintDiff) IsEmpty() bool {
+	return len(d.Dogus) == 0 && len(d.Packages) == 0 && len(d.RegistryConfig) == 0 && len(d.RegistryConfigEncrypted) == 0
+}
+
+// Diff computes the changes that are necessary to get from the old to the new blueprint. Dogus and packages are
+// matched by their name, registry config entries by their flattened key path.
+func Diff(old, new BlueprintV1) BlueprintDiff {
+	return BlueprintDiff{
+		Dogus:                   diffDogus(old.Dogus, new.Dogus),
+		Packages:                diffPackages(old.Packages, new.Packages),
+		RegistryConfig:          diffRegistryConfig(old.RegistryConfig, new.RegistryConfig),
+		RegistryConfigEncrypted: diffEncryptedRegistryConfig(old.RegistryConfigEncrypted, new.RegistryConfigEncrypted),
+	}
+}
+
+func diffDogus(oldDogus, newDogus []TargetDogu) []DoguDiff {
+	oldByName := make(map[string]TargetDogu, len(oldDogus))
+	for _, dogu := range oldDogus {
+		oldByName[dogu.Name] = dogu
+	}
+	newByName := make(map[string]TargetDogu, len(newDogus))
+	for _, dogu := range newDogus {
+		newByName[dogu.Name] = dogu
+	}
+
+	var result []DoguDiff
+	for _, name := range unionOfKeys(oldByName, newByName) {
+		oldDogu, inOld := oldByName[name]
+		newDogu, inNew := newByName[name]
+		changes := diffItem(inOld, inNew, oldDogu.Version, newDogu.Version, oldDogu.TargetState, newDogu.TargetState)
+		if len(changes) > 0 {
+			result = append(result, DoguDiff{Name: name, Old: oldDogu, New: newDogu, Changes: changes})
+		}
+	}
+	return result
+}
+
+func diffPackages(oldPackages, newPackages []TargetPackage) []PackageDiff {
+	oldByName := make(map[string]TargetPackage, len(oldPackages))
+	for _, pkg := range oldPackages {
+		oldByName[pkg.identity()] = pkg
+	}
+	newByName := make(map[string]TargetPackage, len(newPackages))
+	for _, pkg := range newPackages {
+		newByName[pkg.identity()] = pkg
+	}
+
+	var result []PackageDiff
+	for _, name := range unionOfKeys(oldByName, newByName) {
+		oldPkg, inOld := oldByName[name]
+		newPkg, inNew := newByName[name]
+		changes := diffItem(inOld, inNew, oldPkg.Version, newPkg.Version, oldPkg.TargetState, newPkg.TargetState)
+		if len(changes) > 0 {
+			result = append(result, PackageDiff{Name: name, Old: oldPkg, New: newPkg, Changes: changes})
+		}
+	}
+	return result
+}
+
+func diffItem(inOld, inNew bool, oldVersion, newVersion string, oldState, newState TargetState) []ChangeType {
+	if !inOld {
+		return []ChangeType{ChangeTypeAdded}
+	}
+	if !inNew {
+		return []ChangeType{ChangeTypeRemoved}
+	}
+
+	var changes []ChangeType
+	if oldVersion != newVersion {
+		changes = append(changes, ChangeTypeVersionChanged)
+	}
+	if oldState != newState {
+		changes = append(changes, ChangeTypeTargetStateChanged)
+	}
+	return changes
+}
+
+func diffRegistryConfig(oldConfig, newConfig RegistryConfig) []RegistryConfigDiff {
+	oldFlat := oldConfig.Flatten()
+	newFlat := newConfig.Flatten()
+
+	var result []RegistryConfigDiff
+	for _, key := range unionOfKeys(oldFlat, newFlat) {
+		oldValue, inOld := oldFlat[key]
+		newValue, inNew := newFlat[key]
+		switch {
+		case !inOld:
+			result = append(result, RegistryConfigDiff{Key: key, NewValue: newValue, Change: ChangeTypeAdded})
+		case !inNew:
+			result = append(result, RegistryConfigDiff{Key: key, OldValue: oldValue, Change: ChangeTypeRemoved})
+		case !reflect.DeepEqual(oldValue, newValue):
+			result = append(result, RegistryConfigDiff{Key: key, OldValue: oldValue, NewValue: newValue, Change: ChangeTypeValueChanged})
+		}
+	}
+	return result
+}
+
+func diffEncryptedRegistryConfig(oldConfig, newConfig EncryptedRegistryConfig) []RegistryConfigDiff {
+	result := diffRegistryConfig(RegistryConfig(oldConfig), RegistryConfig(newConfig))
+	for i := range result {
+		if result[i].OldValue != nil {
+			result[i].OldValue = redactedValue
+		}
+		if result[i].NewValue != nil {
+			result[i].NewValue = redactedValue
+		}
+	}
+	return result
+}
+
+// unionOfKeys returns the sorted union of the keys of both maps.
+func unionOfKeys[V any](first, second map[string]V) []string {
+	keys := make([]string, 0, len(first)+len(second))
+	for key := range first {
+		keys = append(keys, key)
+	}
+	for key := range second {
+		if _, ok := first[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
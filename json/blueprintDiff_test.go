@@ -0,0 +1,126 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("should return empty diff for equal blueprints", func(t *testing.T) {
+		blueprint := createValidBlueprint()
+		blueprint.RegistryConfig = RegistryConfig{"_global": {"fqdn": "ces.example.com"}}
+
+		actual := Diff(blueprint, blueprint)
+
+		assert.True(t, actual.IsEmpty())
+	})
+	t.Run("should classify dogu changes", func(t *testing.T) {
+		old := BlueprintV1{Dogus: []TargetDogu{
+			{Name: "official/nginx", Version: "1.2.3-4"},
+			{Name: "official/redmine", Version: "5.0.0-1"},
+			{Name: "official/ldap", Version: "2.0.0-1"},
+			{Name: "official/postfix", Version: "3.0.0-1"},
+		}}
+		new := BlueprintV1{Dogus: []TargetDogu{
+			{Name: "official/nginx", Version: "1.2.3-4"},
+			{Name: "official/redmine", Version: "5.1.0-1"},
+			{Name: "official/ldap", Version: "2.0.0-1", TargetState: TargetStateAbsent},
+			{Name: "official/scm", Version: "2.0.0-1"},
+		}}
+
+		actual := Diff(old, new)
+
+		expected := []DoguDiff{
+			{
+				Name:    "official/ldap",
+				Old:     TargetDogu{Name: "official/ldap", Version: "2.0.0-1"},
+				New:     TargetDogu{Name: "official/ldap", Version: "2.0.0-1", TargetState: TargetStateAbsent},
+				Changes: []ChangeType{ChangeTypeTargetStateChanged},
+			},
+			{
+				Name:    "official/postfix",
+				Old:     TargetDogu{Name: "official/postfix", Version: "3.0.0-1"},
+				Changes: []ChangeType{ChangeTypeRemoved},
+			},
+			{
+				Name:    "official/redmine",
+				Old:     TargetDogu{Name: "official/redmine", Version: "5.0.0-1"},
+				New:     TargetDogu{Name: "official/redmine", Version: "5.1.0-1"},
+				Changes: []ChangeType{ChangeTypeVersionChanged},
+			},
+			{
+				Name:    "official/scm",
+				New:     TargetDogu{Name: "official/scm", Version: "2.0.0-1"},
+				Changes: []ChangeType{ChangeTypeAdded},
+			},
+		}
+		assert.Equal(t, expected, actual.Dogus)
+		assert.Empty(t, actual.Packages)
+		assert.Empty(t, actual.RegistryConfig)
+	})
+	t.Run("should classify package changes", func(t *testing.T) {
+		old := BlueprintV1{Packages: []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}}}
+		new := BlueprintV1{Packages: []TargetPackage{{Name: "cesapp", Version: "7.1.0-1", TargetState: TargetStateAbsent}}}
+
+		actual := Diff(old, new)
+
+		expected := []PackageDiff{{
+			Name:    "cesapp",
+			Old:     TargetPackage{Name: "cesapp", Version: "7.0.0-1"},
+			New:     TargetPackage{Name: "cesapp", Version: "7.1.0-1", TargetState: TargetStateAbsent},
+			Changes: []ChangeType{ChangeTypeVersionChanged, ChangeTypeTargetStateChanged},
+		}}
+		assert.Equal(t, expected, actual.Packages)
+	})
+	t.Run("should match packages by package manager", func(t *testing.T) {
+		old := BlueprintV1{Packages: []TargetPackage{{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"}}}
+		new := BlueprintV1{Packages: []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+			{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+		}}
+
+		actual := Diff(old, new)
+
+		expected := []PackageDiff{{
+			Name:    "yum:curl",
+			New:     TargetPackage{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+			Changes: []ChangeType{ChangeTypeAdded},
+		}}
+		assert.Equal(t, expected, actual.Packages)
+	})
+	t.Run("should classify registry config changes by key path", func(t *testing.T) {
+		old := BlueprintV1{
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "old.example.com", "admin_group": "admins"}},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "a"}},
+		}
+		new := BlueprintV1{
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "new.example.com"}, "redmine": {"theme": "dark"}},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "a"}},
+		}
+
+		actual := Diff(old, new)
+
+		expected := []RegistryConfigDiff{
+			{Key: "_global/admin_group", OldValue: "admins", Change: ChangeTypeRemoved},
+			{Key: "_global/fqdn", OldValue: "old.example.com", NewValue: "new.example.com", Change: ChangeTypeValueChanged},
+			{Key: "redmine/theme", NewValue: "dark", Change: ChangeTypeAdded},
+		}
+		assert.Equal(t, expected, actual.RegistryConfig)
+		assert.Empty(t, actual.RegistryConfigEncrypted)
+		assert.False(t, actual.IsEmpty())
+	})
+	t.Run("should redact encrypted registry config values", func(t *testing.T) {
+		old := BlueprintV1{RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "old", "token": "t0k3n"}}}
+		new := BlueprintV1{RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "new", "password": "pw"}}}
+
+		actual := Diff(old, new)
+
+		expected := []RegistryConfigDiff{
+			{Key: "redmine/password", NewValue: "***", Change: ChangeTypeAdded},
+			{Key: "redmine/secret", OldValue: "***", NewValue: "***", Change: ChangeTypeValueChanged},
+			{Key: "redmine/token", OldValue: "***", Change: ChangeTypeRemoved},
+		}
+		assert.Equal(t, expected, actual.RegistryConfigEncrypted)
+	})
+}
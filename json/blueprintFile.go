@@ -0,0 +1,51 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrUnreadableFile is returned by ParseBlueprintFile and ParseBlueprintFileTyped if the blueprint file cannot be
+// read. The underlying error is wrapped as well, so that f. i. errors.Is(err, fs.ErrNotExist) and
+// errors.Is(err, fs.ErrPermission) can be used to find the cause.
+var ErrUnreadableFile = errors.New("could not read blueprint file")
+
+// ParseBlueprintFile reads the blueprint file at the given path and parses it like ParseBlueprint. Errors reading the
+// file wrap ErrUnreadableFile and parse errors wrap ErrInvalidJSON. Both contain the path.
+func ParseBlueprintFile(path string) (GeneralBlueprint, error) {
+	rawBlueprint, err := readBlueprintFile(path)
+	if err != nil {
+		return GeneralBlueprint{}, err
+	}
+
+	blueprint, err := ParseBlueprint(rawBlueprint)
+	if err != nil {
+		return GeneralBlueprint{}, fmt.Errorf("could not parse blueprint file %q: %w", path, err)
+	}
+	return blueprint, nil
+}
+
+// ParseBlueprintFileTyped reads the blueprint file at the given path and parses it like ParseBlueprintTyped. Errors
+// are returned like by ParseBlueprintFile.
+func ParseBlueprintFileTyped(path string) (Blueprint, error) {
+	rawBlueprint, err := readBlueprintFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	blueprint, err := ParseBlueprintTyped(rawBlueprint)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse blueprint file %q: %w", path, err)
+	}
+	return blueprint, nil
+}
+
+func readBlueprintFile(path string) ([]byte, error) {
+	rawBlueprint, err := os.ReadFile(path)
+	if err != nil {
+		// the error of os.ReadFile already contains the path
+		return nil, fmt.Errorf("%w: %w", ErrUnreadableFile, err)
+	}
+	return rawBlueprint, nil
+}
@@ -0,0 +1,76 @@
+package json
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBlueprintFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "blueprint.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestParseBlueprintFile(t *testing.T) {
+	t.Run("should parse blueprint file", func(t *testing.T) {
+		path := writeBlueprintFile(t, `{"blueprintApi": "v1", "blueprintId": "my-blueprint"}`)
+
+		actual, err := ParseBlueprintFile(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, GeneralBlueprint{API: V1}, actual)
+	})
+	t.Run("should fail for missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.json")
+
+		_, err := ParseBlueprintFile(path)
+
+		assert.ErrorIs(t, err, ErrUnreadableFile)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.NotErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, path)
+	})
+	t.Run("should fail for invalid content", func(t *testing.T) {
+		path := writeBlueprintFile(t, `{`)
+
+		_, err := ParseBlueprintFile(path)
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.NotErrorIs(t, err, ErrUnreadableFile)
+		assert.ErrorContains(t, err, path)
+	})
+}
+
+func TestParseBlueprintFileTyped(t *testing.T) {
+	t.Run("should parse blueprint file", func(t *testing.T) {
+		path := writeBlueprintFile(t, `{"blueprintApi": "v1", "blueprintId": "my-blueprint"}`)
+
+		actual, err := ParseBlueprintFileTyped(path)
+
+		require.NoError(t, err)
+		require.IsType(t, &BlueprintV1{}, actual)
+		assert.Equal(t, "my-blueprint", actual.(*BlueprintV1).ID)
+	})
+	t.Run("should fail for directory", func(t *testing.T) {
+		path := t.TempDir()
+
+		_, err := ParseBlueprintFileTyped(path)
+
+		assert.ErrorIs(t, err, ErrUnreadableFile)
+		assert.ErrorContains(t, err, path)
+	})
+	t.Run("should fail for unsupported API version", func(t *testing.T) {
+		path := writeBlueprintFile(t, `{"blueprintApi": "v99"}`)
+
+		_, err := ParseBlueprintFileTyped(path)
+
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+		assert.ErrorContains(t, err, path)
+	})
+}
@@ -0,0 +1,45 @@
+package json
+
+// Inverted returns a teardown blueprint which removes everything the blueprint installs:
+//   - Present dogus and packages are marked as absent and absent ones are kept as they are.
+//   - Ignored dogus and packages are dropped. TargetStateIgnore is only used internally and is rejected by Validate;
+//     a dogu or package which is not mentioned in a blueprint is left as it is anyway.
+//   - The keys of the registry config and of the encrypted registry config are moved to the absent registry config
+//     entries. The encrypted values are dropped so that no secrets are passed on to the teardown blueprint.
+//
+// The blueprint itself is not modified.
+func (b BlueprintV1) Inverted() BlueprintV1 {
+	result := b.DeepCopy()
+
+	var dogus []TargetDogu
+	for _, dogu := range result.Dogus {
+		if dogu.TargetState == TargetStateIgnore {
+			continue
+		}
+		if dogu.TargetState == TargetStatePresent {
+			dogu.TargetState = TargetStateAbsent
+		}
+		dogus = append(dogus, dogu)
+	}
+	result.Dogus = dogus
+
+	var packages []TargetPackage
+	for _, pkg := range result.Packages {
+		if pkg.TargetState == TargetStateIgnore {
+			continue
+		}
+		if pkg.TargetState == TargetStatePresent {
+			pkg.TargetState = TargetStateAbsent
+		}
+		packages = append(packages, pkg)
+	}
+	result.Packages = packages
+
+	result.RegistryConfigAbsent = mergeStrings(result.RegistryConfigAbsent, unionOfKeys(result.RegistryConfig.Flatten(), nil))
+	result.RegistryConfigAbsent = mergeStrings(result.RegistryConfigAbsent,
+		unionOfKeys(RegistryConfig(result.RegistryConfigEncrypted).Flatten(), nil))
+	result.RegistryConfig = nil
+	result.RegistryConfigEncrypted = nil
+
+	return result
+}
@@ -0,0 +1,67 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprintV1_Inverted(t *testing.T) {
+	t.Run("should remove everything the blueprint installs", func(t *testing.T) {
+		sut := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4"},
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+				{Name: "official/scm", TargetState: TargetStateIgnore},
+			},
+			Packages: []TargetPackage{
+				{Name: "cesapp", Version: "7.0.0-1"},
+				{Name: "ces-commons", TargetState: TargetStateIgnore},
+			},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com"}, "redmine": {"theme": "dark"}},
+			RegistryConfigAbsent:    []string{"redmine/theme", "_global/mail"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+		}
+
+		actual := sut.Inverted()
+
+		expected := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStateAbsent},
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+			},
+			Packages:             []TargetPackage{{Name: "cesapp", Version: "7.0.0-1", TargetState: TargetStateAbsent}},
+			RegistryConfigAbsent: []string{"redmine/theme", "_global/mail", "_global/fqdn", "redmine/secret"},
+		}
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("should not modify the original", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.RegistryConfig = RegistryConfig{"_global": {"fqdn": "ces.example.com"}}
+		sut.RegistryConfigAbsent = []string{"redmine/theme"}
+		original := sut.DeepCopy()
+
+		_ = sut.Inverted()
+
+		assert.Equal(t, original, sut)
+	})
+	t.Run("should result in a valid blueprint", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "official/scm", TargetState: TargetStateIgnore})
+		sut.RegistryConfig = RegistryConfig{"_global": {"fqdn": "ces.example.com"}}
+		sut.RegistryConfigEncrypted = EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}}
+
+		actual := sut.Inverted()
+
+		require.NoError(t, actual.Validate())
+		assert.Empty(t, actual.DogusByState(TargetStatePresent))
+		assert.Empty(t, actual.PackagesByState(TargetStatePresent))
+	})
+}
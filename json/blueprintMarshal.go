@@ -0,0 +1,163 @@
+package json
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// MarshalBlueprintV1 marshals the given blueprint to JSON in a canonical form so that equal blueprints always produce
+// equal output. Dogus and packages are sorted by name, the entries of RegistryConfigAbsent are sorted
+// lexicographically and the keys of all registry config maps are emitted in sorted order. The given blueprint is not
+// modified.
+func MarshalBlueprintV1(b BlueprintV1) ([]byte, error) {
+	canonical := b
+	canonical.Dogus = sortedDogus(b.Dogus)
+	canonical.Packages = sortedPackages(b.Packages)
+	canonical.RegistryConfigAbsent = sortedStrings(b.RegistryConfigAbsent)
+
+	// encoding/json already emits map keys in sorted order, so the registry configs need no further treatment.
+	result, err := json.Marshal(canonical)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal blueprint %q: %w", b.ID, err)
+	}
+
+	return result, nil
+}
+
+// MarshalJSONValidated validates the blueprint and marshals it to JSON. Unlike json.Marshal it refuses to emit an
+// invalid blueprint and returns the validation errors instead, so that generated blueprints are not rejected by the
+// receiving end.
+func (b BlueprintV1) MarshalJSONValidated() ([]byte, error) {
+	err := b.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("refusing to marshal invalid blueprint %q: %w", b.ID, err)
+	}
+
+	result, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal blueprint %q: %w", b.ID, err)
+	}
+
+	return result, nil
+}
+
+// CanonicalHash returns the hex encoded SHA-256 digest of the canonical JSON form of the blueprint, see
+// MarshalBlueprintV1. Identical dogus, packages and absent registry config entries are only taken into account once,
+// so that blueprints which only differ in ordering or repeated entries produce the same hash. Like Equal, the hash
+// ignores metadata, comments and empty registry config sections, so that equal blueprints produce the same hash.
+func (b BlueprintV1) CanonicalHash() (string, error) {
+	canonical := b
+	canonical.Metadata = nil
+	canonical.Dogus = sortedDogus(b.Dogus)
+	for i := range canonical.Dogus {
+		canonical.Dogus[i].Comment = ""
+	}
+	canonical.Dogus = dedupe(canonical.Dogus)
+	canonical.Packages = sortedPackages(b.Packages)
+	for i := range canonical.Packages {
+		canonical.Packages[i].Comment = ""
+	}
+	canonical.Packages = dedupe(canonical.Packages)
+	canonical.RegistryConfigAbsent = dedupe(sortedStrings(b.RegistryConfigAbsent))
+	canonical.RegistryConfig = withoutEmptySections(b.RegistryConfig)
+	canonical.RegistryConfigEncrypted = EncryptedRegistryConfig(withoutEmptySections(RegistryConfig(b.RegistryConfigEncrypted)))
+
+	rawBlueprint, err := MarshalBlueprintV1(canonical)
+	if err != nil {
+		return "", fmt.Errorf("could not compute hash of blueprint %q: %w", b.ID, err)
+	}
+
+	sum := sha256.Sum256(rawBlueprint)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// withoutEmptySections returns a shallow copy of the registry config without the sections which contain no keys.
+func withoutEmptySections(config RegistryConfig) RegistryConfig {
+	if config == nil {
+		return nil
+	}
+
+	result := make(RegistryConfig, len(config))
+	for section, entries := range config {
+		if len(entries) > 0 {
+			result[section] = entries
+		}
+	}
+	return result
+}
+
+// dedupe removes consecutive identical values from the given sorted slice in place.
+func dedupe[T comparable](sorted []T) []T {
+	return dedupeFunc(sorted, func(a, b T) bool { return a == b })
+}
+
+// dedupeFunc removes consecutive values which are equal according to the given function from the given sorted slice
+// in place.
+func dedupeFunc[T any](sorted []T, equal func(a, b T) bool) []T {
+	if len(sorted) < 2 {
+		return sorted
+	}
+
+	result := sorted[:1]
+	for _, value := range sorted[1:] {
+		if !equal(value, result[len(result)-1]) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func sortedDogus(dogus []TargetDogu) []TargetDogu {
+	if dogus == nil {
+		return nil
+	}
+
+	result := make([]TargetDogu, len(dogus))
+	copy(result, dogus)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		if result[i].Version != result[j].Version {
+			return result[i].Version < result[j].Version
+		}
+		return result[i].TargetState < result[j].TargetState
+	})
+	return result
+}
+
+func sortedPackages(packages []TargetPackage) []TargetPackage {
+	if packages == nil {
+		return nil
+	}
+
+	result := make([]TargetPackage, len(packages))
+	copy(result, packages)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		if result[i].PackageManager != result[j].PackageManager {
+			return result[i].PackageManager < result[j].PackageManager
+		}
+		if result[i].Version != result[j].Version {
+			return result[i].Version < result[j].Version
+		}
+		return result[i].TargetState < result[j].TargetState
+	})
+	return result
+}
+
+func sortedStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	result := make([]string, len(values))
+	copy(result, values)
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,196 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalBlueprintV1(t *testing.T) {
+	t.Run("should emit sorted output", func(t *testing.T) {
+		sut := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/redmine", Version: "5.0.0-1"},
+				{Name: "official/nginx", Version: "1.2.3-4"},
+			},
+			Packages: []TargetPackage{
+				{Name: "cesapp", Version: "7.0.0-1"},
+				{Name: "ces-commons", Version: "1.0.0-1"},
+			},
+			RegistryConfig: RegistryConfig{
+				"redmine": {"b": "2", "a": "1"},
+				"_global": {"fqdn": "ces.example.com"},
+			},
+			RegistryConfigAbsent: []string{"redmine/b", "_global/admin_group"},
+		}
+
+		actual, err := MarshalBlueprintV1(sut)
+
+		require.NoError(t, err)
+		expected := `{"blueprintApi":"v1","blueprintId":"my-blueprint","cesappVersion":"7.0.0-1",` +
+			`"dogus":[{"name":"official/nginx","version":"1.2.3-4"},{"name":"official/redmine","version":"5.0.0-1"}],` +
+			`"packages":[{"name":"ces-commons","version":"1.0.0-1"},{"name":"cesapp","version":"7.0.0-1"}],` +
+			`"registryConfig":{"_global":{"fqdn":"ces.example.com"},"redmine":{"a":"1","b":"2"}},` +
+			`"registryConfigAbsent":["_global/admin_group","redmine/b"]}`
+		assert.Equal(t, expected, string(actual))
+	})
+	t.Run("should not modify the input", func(t *testing.T) {
+		sut := BlueprintV1{
+			Dogus:                []TargetDogu{{Name: "official/redmine"}, {Name: "official/nginx"}},
+			Packages:             []TargetPackage{{Name: "cesapp"}, {Name: "ces-commons"}},
+			RegistryConfigAbsent: []string{"b", "a"},
+		}
+
+		_, err := MarshalBlueprintV1(sut)
+
+		require.NoError(t, err)
+		assert.Equal(t, "official/redmine", sut.Dogus[0].Name)
+		assert.Equal(t, "cesapp", sut.Packages[0].Name)
+		assert.Equal(t, []string{"b", "a"}, sut.RegistryConfigAbsent)
+	})
+	t.Run("should be independent of the input order", func(t *testing.T) {
+		first := BlueprintV1{
+			Dogus:    []TargetDogu{{Name: "official/redmine"}, {Name: "official/nginx"}},
+			Packages: []TargetPackage{{Name: "cesapp"}, {Name: "ces-commons"}},
+		}
+		second := BlueprintV1{
+			Dogus:    []TargetDogu{{Name: "official/nginx"}, {Name: "official/redmine"}},
+			Packages: []TargetPackage{{Name: "ces-commons"}, {Name: "cesapp"}},
+		}
+
+		firstJson, err := MarshalBlueprintV1(first)
+		require.NoError(t, err)
+		secondJson, err := MarshalBlueprintV1(second)
+		require.NoError(t, err)
+
+		assert.Equal(t, string(firstJson), string(secondJson))
+	})
+	t.Run("should fail for undefined target state", func(t *testing.T) {
+		sut := BlueprintV1{ID: "my-blueprint", Dogus: []TargetDogu{{Name: "official/nginx", TargetState: TargetState(99)}}}
+
+		_, err := MarshalBlueprintV1(sut)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `could not marshal blueprint "my-blueprint"`)
+	})
+}
+
+func TestBlueprintV1_MarshalJSONValidated(t *testing.T) {
+	t.Run("should marshal valid blueprint", func(t *testing.T) {
+		sut := createValidBlueprint()
+
+		actual, err := sut.MarshalJSONValidated()
+
+		require.NoError(t, err)
+		reparsed, err := ParseBlueprintTyped(actual)
+		require.NoError(t, err)
+		assert.Equal(t, &sut, reparsed)
+	})
+	t.Run("should refuse to marshal invalid blueprint", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.CesAppVersion = ""
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "official/scm"})
+
+		actual, err := sut.MarshalJSONValidated()
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorContains(t, err, `refusing to marshal invalid blueprint "my-blueprint"`)
+		assert.ErrorContains(t, err, "cesapp version must not be empty")
+		assert.ErrorContains(t, err, "dogu at index 2 is invalid")
+	})
+	t.Run("should refuse to marshal blueprint with other API version", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.API = TestEmpty
+
+		actual, err := sut.MarshalJSONValidated()
+
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+	})
+}
+
+func TestBlueprintV1_CanonicalHash(t *testing.T) {
+	createBlueprint := func() BlueprintV1 {
+		return BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4"},
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+			},
+			Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}, {Name: "ces-commons", Version: "1.0.0-1"}},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com", "admin_group": "admins"}, "redmine": {"theme": "dark"}},
+			RegistryConfigAbsent:    []string{"redmine/theme", "_global/mail"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+		}
+	}
+
+	t.Run("should return SHA-256 hex digest", func(t *testing.T) {
+		actual, err := createBlueprint().CanonicalHash()
+
+		require.NoError(t, err)
+		assert.Regexp(t, "^[0-9a-f]{64}$", actual)
+	})
+	t.Run("should be independent of ordering and repeated entries", func(t *testing.T) {
+		sut := createBlueprint()
+		other := createBlueprint()
+		other.Dogus = []TargetDogu{other.Dogus[1], other.Dogus[0], other.Dogus[1]}
+		other.Packages[0], other.Packages[1] = other.Packages[1], other.Packages[0]
+		other.RegistryConfigAbsent = []string{"_global/mail", "redmine/theme", "_global/mail"}
+
+		hash, err := sut.CanonicalHash()
+		require.NoError(t, err)
+		otherHash, err := other.CanonicalHash()
+		require.NoError(t, err)
+
+		assert.Equal(t, hash, otherHash)
+		assert.Len(t, other.Dogus, 3, "input must not be modified")
+	})
+	t.Run("should be consistent with Equal", func(t *testing.T) {
+		sut := createBlueprint()
+		other := createBlueprint()
+		other.Metadata = map[string]string{"author": "jane"}
+		other.Dogus[0].Comment = "pinned because of a regression"
+		other.Packages[1].Comment = "needed by cesapp"
+		other.RegistryConfig["scm"] = map[string]interface{}{}
+		other.RegistryConfigEncrypted["scm"] = map[string]interface{}{}
+		other.Dogus = append(other.Dogus, TargetDogu{Name: "official/nginx", Version: "1.2.3-4", Comment: "repeated"})
+		other.Packages = append(other.Packages, other.Packages[0])
+		other.RegistryConfigAbsent = append(other.RegistryConfigAbsent, "redmine/theme")
+		require.True(t, sut.Equal(other))
+		require.True(t, other.Equal(sut))
+
+		hash, err := sut.CanonicalHash()
+		require.NoError(t, err)
+		otherHash, err := other.CanonicalHash()
+		require.NoError(t, err)
+
+		assert.Equal(t, hash, otherHash)
+		assert.Equal(t, "pinned because of a regression", other.Dogus[0].Comment, "input must not be modified")
+	})
+	t.Run("should differ for different content", func(t *testing.T) {
+		sut := createBlueprint()
+		other := createBlueprint()
+		other.Dogus[0].Version = "1.2.3-5"
+
+		hash, err := sut.CanonicalHash()
+		require.NoError(t, err)
+		otherHash, err := other.CanonicalHash()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, hash, otherHash)
+	})
+	t.Run("should fail for undefined target state", func(t *testing.T) {
+		sut := BlueprintV1{ID: "my-blueprint", Dogus: []TargetDogu{{Name: "official/nginx", TargetState: TargetState(99)}}}
+
+		_, err := sut.CanonicalHash()
+
+		assert.ErrorContains(t, err, `could not compute hash of blueprint "my-blueprint"`)
+	})
+}
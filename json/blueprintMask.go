@@ -16,7 +16,7 @@ type GeneralBlueprintMask struct {
 	//
 	// This field MUST NOT be MODIFIED or REMOVED because the API is paramount for distinguishing between different
 	// blueprint mask version implementations.
-	API BlueprintMaskApi `json:"blueprintMaskApi"`
+	API BlueprintMaskApi `json:"blueprintMaskApi" yaml:"blueprintMaskApi"`
 }
 
 // BlueprintMaskV1 describes an abstraction of CES components that should alter a blueprint definition before
@@ -26,11 +26,11 @@ type GeneralBlueprintMask struct {
 // In general additions without changing the version are fine, as long as they don't change semantics. Removal or
 // renaming are breaking changes and require a new blueprint mask API version.
 type BlueprintMaskV1 struct {
-	GeneralBlueprintMask
+	GeneralBlueprintMask `yaml:",inline"`
 	// ID is the unique name of the set over all components. This blueprint mask ID should be used to distinguish
 	// from similar blueprint masks between humans in an easy way. Must not be empty.
-	ID string `json:"blueprintMaskId"`
+	ID string `json:"blueprintMaskId" yaml:"blueprintMaskId"`
 	// Dogus contains a set of dogus which alters the states of the dogus in the blueprint this mask is applied on.
 	// The names and target states of all dogus must not be empty.
-	Dogus []TargetDogu `json:"dogus"`
+	Dogus []TargetDogu `json:"dogus" yaml:"dogus"`
 }
@@ -0,0 +1,112 @@
+package json
+
+import (
+	"fmt"
+)
+
+// Merge combines a base blueprint with an overlay, f. i. an environment-specific blueprint. The result shares no
+// slices or maps with the inputs.
+//
+//   - Dogus and packages of the overlay replace those of the base with the same name. Packages additionally need the
+//     same package manager. New ones are appended.
+//   - Registry configs are merged with RegistryConfig.Merge, so the overlay takes precedence per key.
+//   - The entries of RegistryConfigAbsent are unioned.
+//   - The ID of the overlay is used if it is not empty.
+//   - Metadata is merged per key with the overlay taking precedence.
+//
+// An error is returned if the blueprint API versions or cesapp versions of both blueprints differ. Empty values do not
+// conflict and are taken from the other blueprint.
+func Merge(base, overlay BlueprintV1) (BlueprintV1, error) {
+	api, err := mergeField("blueprint API", string(base.API), string(overlay.API))
+	if err != nil {
+		return BlueprintV1{}, err
+	}
+	cesAppVersion, err := mergeField("cesapp version", base.CesAppVersion, overlay.CesAppVersion)
+	if err != nil {
+		return BlueprintV1{}, err
+	}
+
+	result := base.DeepCopy()
+	result.API = BlueprintApi(api)
+	result.CesAppVersion = cesAppVersion
+	if overlay.ID != "" {
+		result.ID = overlay.ID
+	}
+	result.Dogus = mergeDogus(result.Dogus, overlay.Dogus)
+	result.Packages = mergePackages(result.Packages, overlay.Packages)
+	result.RegistryConfig = base.RegistryConfig.Merge(overlay.RegistryConfig)
+	result.RegistryConfigEncrypted = EncryptedRegistryConfig(
+		RegistryConfig(base.RegistryConfigEncrypted).Merge(RegistryConfig(overlay.RegistryConfigEncrypted)))
+	result.RegistryConfigAbsent = mergeStrings(result.RegistryConfigAbsent, overlay.RegistryConfigAbsent)
+	for key, value := range overlay.Metadata {
+		if result.Metadata == nil {
+			result.Metadata = map[string]string{}
+		}
+		result.Metadata[key] = value
+	}
+
+	return result, nil
+}
+
+func mergeField(fieldName string, base, overlay string) (string, error) {
+	if base != "" && overlay != "" && base != overlay {
+		return "", fmt.Errorf("cannot merge blueprints with different %s: %q and %q", fieldName, base, overlay)
+	}
+	if overlay != "" {
+		return overlay, nil
+	}
+	return base, nil
+}
+
+func mergeDogus(base, overlay []TargetDogu) []TargetDogu {
+	result := base
+	for _, dogu := range overlay {
+		replaced := false
+		for i := range result {
+			if result[i].Name == dogu.Name {
+				result[i] = dogu
+				replaced = true
+			}
+		}
+		if !replaced {
+			result = append(result, dogu)
+		}
+	}
+	return result
+}
+
+func mergePackages(base, overlay []TargetPackage) []TargetPackage {
+	result := base
+	for _, pkg := range overlay {
+		replaced := false
+		for i := range result {
+			if result[i].identity() == pkg.identity() {
+				result[i] = pkg
+				replaced = true
+			}
+		}
+		if !replaced {
+			result = append(result, pkg)
+		}
+	}
+	return result
+}
+
+func mergeStrings(base, overlay []string) []string {
+	result := base
+	for _, value := range overlay {
+		if !containsString(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("should let overlay win", func(t *testing.T) {
+		base := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "base",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4"},
+				{Name: "official/redmine", Version: "5.0.0-1"},
+			},
+			Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "base.example.com", "admin_group": "admins"}},
+			RegistryConfigAbsent:    []string{"redmine/theme", "_global/mail"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "base"}},
+		}
+		overlay := BlueprintV1{
+			ID: "production",
+			Dogus: []TargetDogu{
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+				{Name: "official/scm", Version: "2.0.0-1"},
+			},
+			Packages:                []TargetPackage{{Name: "ces-commons", Version: "1.0.0-1"}},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "prod.example.com"}, "scm": {"url": "scm"}},
+			RegistryConfigAbsent:    []string{"_global/mail", "scm/theme"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"scm": {"secret": "prod"}},
+		}
+
+		actual, err := Merge(base, overlay)
+
+		require.NoError(t, err)
+		expected := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "production",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4"},
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+				{Name: "official/scm", Version: "2.0.0-1"},
+			},
+			Packages: []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}, {Name: "ces-commons", Version: "1.0.0-1"}},
+			RegistryConfig: RegistryConfig{
+				"_global": {"fqdn": "prod.example.com", "admin_group": "admins"},
+				"scm":     {"url": "scm"},
+			},
+			RegistryConfigAbsent:    []string{"redmine/theme", "_global/mail", "scm/theme"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "base"}, "scm": {"secret": "prod"}},
+		}
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("should not modify the inputs", func(t *testing.T) {
+		base := BlueprintV1{
+			Dogus:          []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}},
+			RegistryConfig: RegistryConfig{"_global": {"fqdn": "base.example.com"}},
+		}
+		overlay := BlueprintV1{
+			Dogus:          []TargetDogu{{Name: "official/nginx", Version: "1.3.0-1"}},
+			RegistryConfig: RegistryConfig{"_global": {"fqdn": "prod.example.com"}, "scm": {"url": "scm"}},
+		}
+
+		actual, err := Merge(base, overlay)
+		require.NoError(t, err)
+		actual.RegistryConfig["scm"]["url"] = "changed"
+
+		assert.Equal(t, "1.2.3-4", base.Dogus[0].Version)
+		assert.Equal(t, RegistryConfig{"_global": {"fqdn": "base.example.com"}}, base.RegistryConfig)
+		assert.Equal(t, "scm", overlay.RegistryConfig["scm"]["url"])
+	})
+	t.Run("should replace packages of the same package manager only", func(t *testing.T) {
+		base := BlueprintV1{Packages: []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "yum"},
+		}}
+		overlay := BlueprintV1{Packages: []TargetPackage{{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"}}}
+
+		actual, err := Merge(base, overlay)
+
+		require.NoError(t, err)
+		assert.Equal(t, []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+			{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+		}, actual.Packages)
+	})
+	t.Run("should merge metadata", func(t *testing.T) {
+		base := BlueprintV1{Metadata: map[string]string{"author": "jane", "ticket": "CES-1"}}
+		overlay := BlueprintV1{Metadata: map[string]string{"ticket": "CES-2"}}
+
+		actual, err := Merge(base, overlay)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"author": "jane", "ticket": "CES-2"}, actual.Metadata)
+		assert.Equal(t, "CES-1", base.Metadata["ticket"])
+	})
+	t.Run("should take cesapp version from overlay if base has none", func(t *testing.T) {
+		actual, err := Merge(BlueprintV1{}, BlueprintV1{CesAppVersion: "7.0.0-1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "7.0.0-1", actual.CesAppVersion)
+	})
+	t.Run("should fail for different cesapp versions", func(t *testing.T) {
+		_, err := Merge(BlueprintV1{CesAppVersion: "7.0.0-1"}, BlueprintV1{CesAppVersion: "7.1.0-1"})
+
+		assert.ErrorContains(t, err, `cannot merge blueprints with different cesapp version: "7.0.0-1" and "7.1.0-1"`)
+	})
+	t.Run("should fail for different API versions", func(t *testing.T) {
+		_, err := Merge(BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: V1}}, BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: TestEmpty}})
+
+		assert.ErrorContains(t, err, `cannot merge blueprints with different blueprint API: "v1" and "test/empty"`)
+	})
+}
@@ -0,0 +1,32 @@
+package json
+
+import "strings"
+
+// Normalize rewrites the names of the blueprint into their canonical form, so that f. i. "Official/Nginx " and
+// "official/nginx" are treated as the same dogu by Equal, Validate and Diff. The following fields are normalized:
+//   - Dogus[].Name: surrounding whitespace is removed from the name as well as from its namespace and simple name,
+//     and all letters are lowercased, f. i. " Official / Nginx" becomes "official/nginx".
+//   - Packages[].Name and Packages[].PackageManager: surrounding whitespace is removed and all letters are lowercased.
+//
+// All other fields, especially versions and registry config keys, are kept as they are. Normalize is idempotent.
+func (b *BlueprintV1) Normalize() {
+	for i := range b.Dogus {
+		b.Dogus[i].Name = normalizeDoguName(b.Dogus[i].Name)
+	}
+	for i := range b.Packages {
+		b.Packages[i].Name = normalizeName(b.Packages[i].Name)
+		b.Packages[i].PackageManager = normalizeName(b.Packages[i].PackageManager)
+	}
+}
+
+func normalizeDoguName(name string) string {
+	parts := strings.Split(name, doguNameSeparator)
+	for i := range parts {
+		parts[i] = normalizeName(parts[i])
+	}
+	return strings.Join(parts, doguNameSeparator)
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
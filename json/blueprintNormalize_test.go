@@ -0,0 +1,81 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprintV1_Normalize(t *testing.T) {
+	t.Run("should normalize dogu and package names", func(t *testing.T) {
+		sut := BlueprintV1{
+			Dogus: []TargetDogu{
+				{Name: "Official/Nginx", Version: "1.2.3-4"},
+				{Name: "official/nginx ", Version: "1.2.3-4"},
+				{Name: " OFFICIAL / REDMINE\t", Version: "5.0.0-1"},
+				{Name: "nginx"},
+			},
+			Packages: []TargetPackage{{Name: " CesApp ", Version: "7.0.0-1", PackageManager: " APT"}},
+		}
+
+		sut.Normalize()
+
+		assert.Equal(t, []TargetDogu{
+			{Name: "official/nginx", Version: "1.2.3-4"},
+			{Name: "official/nginx", Version: "1.2.3-4"},
+			{Name: "official/redmine", Version: "5.0.0-1"},
+			{Name: "nginx"},
+		}, sut.Dogus)
+		assert.Equal(t, []TargetPackage{{Name: "cesapp", Version: "7.0.0-1", PackageManager: "apt"}}, sut.Packages)
+	})
+	t.Run("should keep other fields", func(t *testing.T) {
+		sut := BlueprintV1{
+			ID:             "My-Blueprint",
+			Dogus:          []TargetDogu{{Name: "official/nginx", Version: " 1.2.3-4", Comment: "Pinned"}},
+			RegistryConfig: RegistryConfig{"Nginx": {"Theme": "Dark"}},
+		}
+		expected := sut.DeepCopy()
+
+		sut.Normalize()
+
+		assert.Equal(t, expected, sut)
+	})
+	t.Run("should let validation detect duplicates", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "Official/Nginx ", Version: "1.2.3-4"})
+		require.NoError(t, sut.Validate())
+
+		sut.Normalize()
+
+		assert.ErrorContains(t, sut.Validate(), `dogu "official/nginx" must not be contained more than once (index 0, 2)`)
+	})
+	t.Run("should be idempotent", func(t *testing.T) {
+		sut := BlueprintV1{
+			Dogus:    []TargetDogu{{Name: " Official / Nginx", Version: "1.2.3-4"}},
+			Packages: []TargetPackage{{Name: "CesApp", PackageManager: "Yum "}},
+		}
+
+		sut.Normalize()
+		once := sut.DeepCopy()
+		sut.Normalize()
+
+		assert.Equal(t, once, sut)
+	})
+	t.Run("should survive a round trip", func(t *testing.T) {
+		sut := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			Dogus:            []TargetDogu{{Name: "OFFICIAL/NGINX", Version: "1.2.3-4"}},
+		}
+		sut.Normalize()
+
+		raw, err := MarshalBlueprintV1(sut)
+		require.NoError(t, err)
+		parsed, err := ParseBlueprintTyped(raw)
+		require.NoError(t, err)
+		reparsed := parsed.(*BlueprintV1)
+		reparsed.Normalize()
+
+		assert.Equal(t, sut, *reparsed)
+	})
+}
@@ -0,0 +1,157 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrInvalidJSON is returned if a blueprint is no valid JSON or does not match the structure of its blueprint API
+	// version.
+	ErrInvalidJSON = errors.New("invalid blueprint JSON")
+	// ErrMissingAPIVersion is returned if a blueprint does not contain a blueprint API version.
+	ErrMissingAPIVersion = errors.New("missing blueprint API version")
+	// ErrUnsupportedAPIVersion is returned if no parser exists for the blueprint API version of a blueprint.
+	ErrUnsupportedAPIVersion = errors.New("unsupported blueprint API version")
+)
+
+// BlueprintTestEmpty is the parsed representation of a blueprint with the TestEmpty API identifier. It carries no
+// further content and only serves as a marker in tests.
+type BlueprintTestEmpty struct {
+	GeneralBlueprint
+}
+
+// GetID returns an empty ID as TestEmpty blueprints carry no content.
+func (b BlueprintTestEmpty) GetID() string {
+	return ""
+}
+
+// GetDogus returns no dogus as TestEmpty blueprints carry no content.
+func (b BlueprintTestEmpty) GetDogus() []TargetDogu {
+	return nil
+}
+
+// Validate checks that the blueprint has the API version TestEmpty.
+func (b BlueprintTestEmpty) Validate() error {
+	if b.API != TestEmpty {
+		return fmt.Errorf("%w %q, expected %q", ErrUnsupportedAPIVersion, b.API, TestEmpty)
+	}
+	return nil
+}
+
+// ParseBlueprintTyped parses the given byte slice into the concrete blueprint type matching its blueprint API
+// version. It returns a *BlueprintV1 for V1 blueprints and a *BlueprintTestEmpty for TestEmpty blueprints. Blueprints
+// of other API versions are parsed by the parser registered with RegisterBlueprintParser or result in an error.
+func ParseBlueprintTyped(rawBlueprint []byte) (Blueprint, error) {
+	return ParseWithRegistry(rawBlueprint)
+}
+
+// ParseBlueprintStrict works like ParseBlueprintTyped but fails if the blueprint contains fields which are unknown to
+// the blueprint API version, f. i. a misspelled "dugos" instead of "dogus". The returned error names the unexpected
+// field. Blueprints of other API versions than those of this package are parsed by the parser registered with
+// RegisterStrictBlueprintParser or result in an error.
+func ParseBlueprintStrict(rawBlueprint []byte) (Blueprint, error) {
+	return parseWith(strictBlueprintParsers, rawBlueprint)
+}
+
+// ParseBlueprints parses a document containing a JSON array of blueprints into GeneralBlueprints in the order of the
+// array, so that the blueprint version of each element can be determined. A document containing a single blueprint
+// object is parsed into a slice with one element. If an element is malformed the returned error names its index.
+func ParseBlueprints(rawBlueprints []byte) ([]GeneralBlueprint, error) {
+	trimmed := bytes.TrimLeft(rawBlueprints, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		blueprint, err := ParseBlueprint(rawBlueprints)
+		if err != nil {
+			return nil, err
+		}
+		return []GeneralBlueprint{blueprint}, nil
+	}
+
+	var rawElements []json.RawMessage
+	err := json.Unmarshal(rawBlueprints, &rawElements)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse blueprint array: %w: %w", ErrInvalidJSON, err)
+	}
+
+	result := make([]GeneralBlueprint, 0, len(rawElements))
+	for i, rawElement := range rawElements {
+		blueprint, err := ParseBlueprint(rawElement)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse blueprint at index %d: %w", i, err)
+		}
+		result = append(result, blueprint)
+	}
+
+	return result, nil
+}
+
+// ParseBlueprintContext reads a blueprint from the given reader and parses it into a GeneralBlueprint like
+// ParseBlueprint. If the context is done before the blueprint was read completely, ctx.Err() is returned immediately
+// even if the reader is blocked. In that case the reader is not read any further once a pending read returns; callers
+// should close the underlying source, f. i. an HTTP request body, to release it.
+//
+// At most the MaxBytes of DefaultParseLimits are read, so that a slow and huge body cannot exhaust the memory. A
+// larger blueprint results in ErrLimitExceeded.
+func ParseBlueprintContext(ctx context.Context, r io.Reader) (GeneralBlueprint, error) {
+	err := ctx.Err()
+	if err != nil {
+		return GeneralBlueprint{}, err
+	}
+
+	type readResult struct {
+		rawBlueprint []byte
+		err          error
+	}
+	resultChan := make(chan readResult, 1)
+	go func() {
+		maxBytes := DefaultParseLimits().MaxBytes
+		rawBlueprint, readErr := io.ReadAll(io.LimitReader(&contextReader{ctx: ctx, reader: r}, int64(maxBytes)+1))
+		if readErr == nil && len(rawBlueprint) > maxBytes {
+			readErr = fmt.Errorf("%w: blueprint has more than %d bytes", ErrLimitExceeded, maxBytes)
+		}
+		resultChan <- readResult{rawBlueprint: rawBlueprint, err: readErr}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return GeneralBlueprint{}, ctx.Err()
+	case result := <-resultChan:
+		if result.err != nil {
+			if ctx.Err() != nil {
+				return GeneralBlueprint{}, ctx.Err()
+			}
+			return GeneralBlueprint{}, fmt.Errorf("could not read blueprint: %w", result.err)
+		}
+		return ParseBlueprint(result.rawBlueprint)
+	}
+}
+
+// contextReader stops reading from the wrapped reader as soon as its context is done.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+// Read reads from the wrapped reader unless the context is done.
+func (cr *contextReader) Read(p []byte) (int, error) {
+	err := cr.ctx.Err()
+	if err != nil {
+		return 0, err
+	}
+	return cr.reader.Read(p)
+}
+
+func unsupportedAPIVersionError(api BlueprintApi) error {
+	if api == "" {
+		return ErrMissingAPIVersion
+	}
+	return fmt.Errorf("%w %q", ErrUnsupportedAPIVersion, api)
+}
+
+func invalidBlueprintError(api BlueprintApi, err error) error {
+	return fmt.Errorf("could not parse blueprint with API version %q: %w: %w", api, ErrInvalidJSON, err)
+}
@@ -0,0 +1,123 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is returned by ParseBlueprintLimited if a blueprint exceeds one of the configured ParseLimits.
+var ErrLimitExceeded = errors.New("blueprint exceeds parse limit")
+
+const (
+	defaultMaxBytes              = 10 * 1024 * 1024
+	defaultMaxDogus              = 1000
+	defaultMaxPackages           = 1000
+	defaultMaxRegistryConfigKeys = 100000
+)
+
+// ParseLimits restricts the size of blueprints accepted by ParseBlueprintLimited. Fields with a value of zero or less
+// use the corresponding value of DefaultParseLimits, so that every limit is finite.
+type ParseLimits struct {
+	// MaxBytes is the maximum size of the raw blueprint in bytes.
+	MaxBytes int
+	// MaxDogus is the maximum number of dogus.
+	MaxDogus int
+	// MaxPackages is the maximum number of packages.
+	MaxPackages int
+	// MaxRegistryConfigKeys is the maximum number of keys in the registry config, the encrypted registry config and the
+	// absent registry config entries combined. Keys of JSON objects nested in registry config values count as well.
+	MaxRegistryConfigKeys int
+}
+
+// DefaultParseLimits returns generous limits which are not reached by regular blueprints.
+func DefaultParseLimits() ParseLimits {
+	return ParseLimits{
+		MaxBytes:              defaultMaxBytes,
+		MaxDogus:              defaultMaxDogus,
+		MaxPackages:           defaultMaxPackages,
+		MaxRegistryConfigKeys: defaultMaxRegistryConfigKeys,
+	}
+}
+
+func (l ParseLimits) withDefaults() ParseLimits {
+	defaults := DefaultParseLimits()
+	if l.MaxBytes <= 0 {
+		l.MaxBytes = defaults.MaxBytes
+	}
+	if l.MaxDogus <= 0 {
+		l.MaxDogus = defaults.MaxDogus
+	}
+	if l.MaxPackages <= 0 {
+		l.MaxPackages = defaults.MaxPackages
+	}
+	if l.MaxRegistryConfigKeys <= 0 {
+		l.MaxRegistryConfigKeys = defaults.MaxRegistryConfigKeys
+	}
+	return l
+}
+
+// ParseBlueprintLimited works like ParseBlueprintTyped but fails with ErrLimitExceeded if the blueprint exceeds the
+// given limits. The size of the input is checked before it is parsed so that services accepting blueprints from
+// untrusted sources do not allocate for oversized input. Only the content of V1 blueprints is limited.
+func ParseBlueprintLimited(rawBlueprint []byte, limits ParseLimits) (Blueprint, error) {
+	limits = limits.withDefaults()
+	if len(rawBlueprint) > limits.MaxBytes {
+		return nil, fmt.Errorf("%w: blueprint has %d bytes, at most %d bytes are allowed", ErrLimitExceeded, len(rawBlueprint), limits.MaxBytes)
+	}
+
+	blueprint, err := ParseBlueprintTyped(rawBlueprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if blueprintV1, ok := blueprint.(*BlueprintV1); ok {
+		err = blueprintV1.checkLimits(limits)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blueprint, nil
+}
+
+func (b BlueprintV1) checkLimits(limits ParseLimits) error {
+	var errs []error
+
+	if len(b.Dogus) > limits.MaxDogus {
+		errs = append(errs, fmt.Errorf("%w: blueprint contains %d dogus, at most %d are allowed", ErrLimitExceeded, len(b.Dogus), limits.MaxDogus))
+	}
+	if len(b.Packages) > limits.MaxPackages {
+		errs = append(errs, fmt.Errorf("%w: blueprint contains %d packages, at most %d are allowed", ErrLimitExceeded, len(b.Packages), limits.MaxPackages))
+	}
+
+	registryConfigKeys := countRegistryConfigKeys(b.RegistryConfig) +
+		countRegistryConfigKeys(RegistryConfig(b.RegistryConfigEncrypted)) + len(b.RegistryConfigAbsent)
+	if registryConfigKeys > limits.MaxRegistryConfigKeys {
+		errs = append(errs, fmt.Errorf("%w: blueprint contains %d registry config keys, at most %d are allowed",
+			ErrLimitExceeded, registryConfigKeys, limits.MaxRegistryConfigKeys))
+	}
+
+	return errors.Join(errs...)
+}
+
+func countRegistryConfigKeys(config RegistryConfig) int {
+	count := 0
+	for _, value := range config.Flatten() {
+		count += 1 + countNestedKeys(value)
+	}
+	return count
+}
+
+func countNestedKeys(value interface{}) int {
+	count := 0
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for _, nestedValue := range typedValue {
+			count += 1 + countNestedKeys(nestedValue)
+		}
+	case []interface{}:
+		for _, nestedValue := range typedValue {
+			count += countNestedKeys(nestedValue)
+		}
+	}
+	return count
+}
@@ -0,0 +1,63 @@
+package json
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlueprintLimited(t *testing.T) {
+	rawBlueprint := []byte(`{
+		"blueprintApi": "v1",
+		"blueprintId": "my-blueprint",
+		"dogus": [{"name": "official/nginx", "version": "1.2.3-4"}, {"name": "official/redmine", "version": "5.0.0-1"}],
+		"packages": [{"name": "cesapp", "version": "7.0.0-1"}],
+		"registryConfig": {"_global": {"fqdn": "ces.example.com"}, "redmine": {"settings": {"theme": "dark", "plugins": [{"name": "agile"}]}}},
+		"registryConfigAbsent": ["redmine/theme"],
+		"registryConfigEncrypted": {"redmine": {"secret": "s3cr3t"}}
+	}`)
+
+	t.Run("should parse blueprint within default limits", func(t *testing.T) {
+		actual, err := ParseBlueprintLimited(rawBlueprint, ParseLimits{})
+
+		require.NoError(t, err)
+		require.IsType(t, &BlueprintV1{}, actual)
+		assert.Len(t, actual.(*BlueprintV1).Dogus, 2)
+	})
+	t.Run("should fail for too large input before parsing", func(t *testing.T) {
+		_, err := ParseBlueprintLimited([]byte(`{"blueprintApi": "v1", `), ParseLimits{MaxBytes: 10})
+
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+		assert.ErrorContains(t, err, "blueprint has 23 bytes, at most 10 bytes are allowed")
+	})
+	t.Run("should fail for too many dogus and packages", func(t *testing.T) {
+		_, err := ParseBlueprintLimited(rawBlueprint, ParseLimits{MaxDogus: 1, MaxPackages: 1})
+
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+		assert.ErrorContains(t, err, "blueprint contains 2 dogus, at most 1 are allowed")
+		assert.NotContains(t, err.Error(), "packages")
+	})
+	t.Run("should count nested registry config keys", func(t *testing.T) {
+		_, err := ParseBlueprintLimited(rawBlueprint, ParseLimits{MaxRegistryConfigKeys: 6})
+
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+		assert.ErrorContains(t, err, "blueprint contains 7 registry config keys, at most 6 are allowed")
+
+		_, err = ParseBlueprintLimited(rawBlueprint, ParseLimits{MaxRegistryConfigKeys: 7})
+		assert.NoError(t, err)
+	})
+	t.Run("should return parse errors", func(t *testing.T) {
+		_, err := ParseBlueprintLimited([]byte(`{`), ParseLimits{})
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+	})
+	t.Run("should apply default byte limit", func(t *testing.T) {
+		oversized := append([]byte(`{"blueprintApi": "v1", "blueprintId": "`), bytes.Repeat([]byte("a"), defaultMaxBytes)...)
+
+		_, err := ParseBlueprintLimited(oversized, ParseLimits{})
+
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+	})
+}
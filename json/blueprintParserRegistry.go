@@ -0,0 +1,161 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BlueprintParser parses a raw blueprint of a specific blueprint API version into its concrete blueprint type.
+type BlueprintParser func(rawBlueprint []byte) (Blueprint, error)
+
+var (
+	blueprintParsersMutex sync.RWMutex
+	blueprintParsers      = map[BlueprintApi]BlueprintParser{
+		V1:        parseBlueprintV1,
+		TestEmpty: parseBlueprintTestEmpty,
+	}
+	strictBlueprintParsers = map[BlueprintApi]BlueprintParser{
+		V1:        parseBlueprintV1Strict,
+		TestEmpty: parseBlueprintTestEmptyStrict,
+	}
+)
+
+// RegisterBlueprintParser registers a parser for the given blueprint API version so that ParseWithRegistry can
+// dispatch blueprints of this version to it. This allows other modules to plug in further blueprint API versions.
+// RegisterBlueprintParser panics if the parser is nil or if a parser is already registered for the API version.
+func RegisterBlueprintParser(api BlueprintApi, parse BlueprintParser) {
+	registerParser(blueprintParsers, "blueprint parser", api, parse)
+}
+
+// RegisterStrictBlueprintParser registers a strict parser for the given blueprint API version so that
+// ParseBlueprintStrict can dispatch blueprints of this version to it. A strict parser must fail for fields which are
+// unknown to the blueprint API version. RegisterStrictBlueprintParser panics if the parser is nil or if a strict parser
+// is already registered for the API version.
+func RegisterStrictBlueprintParser(api BlueprintApi, parse BlueprintParser) {
+	registerParser(strictBlueprintParsers, "strict blueprint parser", api, parse)
+}
+
+func registerParser(parsers map[BlueprintApi]BlueprintParser, kind string, api BlueprintApi, parse BlueprintParser) {
+	blueprintParsersMutex.Lock()
+	defer blueprintParsersMutex.Unlock()
+
+	if parse == nil {
+		panic(fmt.Sprintf("%s for API version %q must not be nil", kind, api))
+	}
+	if _, exists := parsers[api]; exists {
+		panic(fmt.Sprintf("%s for API version %q is already registered", kind, api))
+	}
+
+	parsers[api] = parse
+}
+
+// SupportedBlueprintApis returns the blueprint API versions for which a parser is registered, sorted by their name.
+// These are the versions of this package and those registered with RegisterBlueprintParser.
+func SupportedBlueprintApis() []BlueprintApi {
+	blueprintParsersMutex.RLock()
+	defer blueprintParsersMutex.RUnlock()
+
+	result := make([]BlueprintApi, 0, len(blueprintParsers))
+	for api := range blueprintParsers {
+		result = append(result, api)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// ParseWithRegistry reads the blueprint API version of the given blueprint and parses it with the parser registered for
+// this version. An error is returned if no parser is registered for the API version.
+func ParseWithRegistry(rawBlueprint []byte) (Blueprint, error) {
+	return parseWith(blueprintParsers, rawBlueprint)
+}
+
+func parseWith(parsers map[BlueprintApi]BlueprintParser, rawBlueprint []byte) (Blueprint, error) {
+	generalBlueprint, err := ParseBlueprint(rawBlueprint)
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintParsersMutex.RLock()
+	parse, ok := parsers[generalBlueprint.API]
+	blueprintParsersMutex.RUnlock()
+	if !ok {
+		return nil, unsupportedAPIVersionError(generalBlueprint.API)
+	}
+
+	return parse(rawBlueprint)
+}
+
+func parseBlueprintV1(rawBlueprint []byte) (Blueprint, error) {
+	blueprint := &BlueprintV1{}
+	err := json.Unmarshal(rawBlueprint, blueprint)
+	if err != nil {
+		return nil, invalidBlueprintError(V1, err)
+	}
+
+	return blueprint, nil
+}
+
+func parseBlueprintV1Strict(rawBlueprint []byte) (Blueprint, error) {
+	blueprint := &BlueprintV1{}
+	err := unmarshalStrict(rawBlueprint, blueprint)
+	if err != nil {
+		return nil, invalidBlueprintError(V1, err)
+	}
+
+	return blueprint, nil
+}
+
+// parseBlueprintTestEmpty makes sure that a blueprint with the test-only API version TestEmpty carries no payload that
+// could be applied to a CES instance.
+func parseBlueprintTestEmpty(rawBlueprint []byte) (Blueprint, error) {
+	content := BlueprintV1{}
+	err := json.Unmarshal(rawBlueprint, &content)
+	if err != nil {
+		return nil, invalidBlueprintError(TestEmpty, err)
+	}
+
+	var fields []string
+	if len(content.Dogus) > 0 {
+		fields = append(fields, "dogus")
+	}
+	if len(content.Packages) > 0 {
+		fields = append(fields, "packages")
+	}
+	if len(content.RegistryConfig) > 0 {
+		fields = append(fields, "registryConfig")
+	}
+	if len(content.RegistryConfigAbsent) > 0 {
+		fields = append(fields, "registryConfigAbsent")
+	}
+	if len(content.RegistryConfigEncrypted) > 0 {
+		fields = append(fields, "registryConfigEncrypted")
+	}
+	if len(fields) > 0 {
+		return nil, fmt.Errorf("blueprint with API version %q must be empty but contains %s", TestEmpty, strings.Join(fields, ", "))
+	}
+
+	return &BlueprintTestEmpty{GeneralBlueprint: GeneralBlueprint{API: TestEmpty}}, nil
+}
+
+// parseBlueprintTestEmptyStrict rejects every field besides the blueprint API version as a blueprint with the API
+// version TestEmpty carries no content.
+func parseBlueprintTestEmptyStrict(rawBlueprint []byte) (Blueprint, error) {
+	blueprint := &BlueprintTestEmpty{}
+	err := unmarshalStrict(rawBlueprint, blueprint)
+	if err != nil {
+		return nil, invalidBlueprintError(TestEmpty, err)
+	}
+
+	return blueprint, nil
+}
+
+// unmarshalStrict works like json.Unmarshal but fails for fields which are unknown to the target type.
+func unmarshalStrict(rawBlueprint []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(rawBlueprint))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
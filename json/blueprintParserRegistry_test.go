@@ -0,0 +1,145 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unregisterBlueprintParser(api BlueprintApi) {
+	blueprintParsersMutex.Lock()
+	defer blueprintParsersMutex.Unlock()
+	delete(blueprintParsers, api)
+	delete(strictBlueprintParsers, api)
+}
+
+func TestRegisterBlueprintParser(t *testing.T) {
+	t.Run("should dispatch to registered parser", func(t *testing.T) {
+		const api BlueprintApi = "test/custom"
+		defer unregisterBlueprintParser(api)
+		RegisterBlueprintParser(api, func(rawBlueprint []byte) (Blueprint, error) {
+			return &BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: api}, ID: string(rawBlueprint)}, nil
+		})
+
+		actual, err := ParseWithRegistry([]byte(`{"blueprintApi": "test/custom"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, api, actual.GetAPI())
+		assert.Equal(t, `{"blueprintApi": "test/custom"}`, actual.GetID())
+	})
+	t.Run("should return error of registered parser", func(t *testing.T) {
+		const api BlueprintApi = "test/failing"
+		defer unregisterBlueprintParser(api)
+		RegisterBlueprintParser(api, func([]byte) (Blueprint, error) {
+			return nil, assert.AnError
+		})
+
+		_, err := ParseWithRegistry([]byte(`{"blueprintApi": "test/failing"}`))
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("should panic for already registered API version", func(t *testing.T) {
+		assert.PanicsWithValue(t, `blueprint parser for API version "v1" is already registered`, func() {
+			RegisterBlueprintParser(V1, parseBlueprintV1)
+		})
+	})
+	t.Run("should panic for nil parser", func(t *testing.T) {
+		assert.PanicsWithValue(t, `blueprint parser for API version "test/nil" must not be nil`, func() {
+			RegisterBlueprintParser("test/nil", nil)
+		})
+	})
+}
+
+func TestSupportedBlueprintApis(t *testing.T) {
+	t.Run("should return sorted built-in versions", func(t *testing.T) {
+		assert.Equal(t, []BlueprintApi{TestEmpty, V1}, SupportedBlueprintApis())
+	})
+	t.Run("should contain registered versions", func(t *testing.T) {
+		const api BlueprintApi = "v3"
+		defer unregisterBlueprintParser(api)
+		RegisterBlueprintParser(api, parseBlueprintV1)
+
+		assert.Equal(t, []BlueprintApi{TestEmpty, V1, api}, SupportedBlueprintApis())
+		assert.True(t, api.IsSupported())
+		assert.NoError(t, api.Validate())
+	})
+	t.Run("should not be modifiable", func(t *testing.T) {
+		apis := SupportedBlueprintApis()
+		apis[0] = "v99"
+
+		assert.False(t, BlueprintApi("v99").IsSupported())
+		assert.Equal(t, []BlueprintApi{TestEmpty, V1}, SupportedBlueprintApis())
+	})
+}
+
+func TestRegisterStrictBlueprintParser(t *testing.T) {
+	t.Run("should dispatch strict parsing to registered strict parser", func(t *testing.T) {
+		const api BlueprintApi = "test/custom"
+		defer unregisterBlueprintParser(api)
+		RegisterBlueprintParser(api, func([]byte) (Blueprint, error) {
+			return nil, assert.AnError
+		})
+		RegisterStrictBlueprintParser(api, func(rawBlueprint []byte) (Blueprint, error) {
+			return &BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: api}, ID: "strict"}, nil
+		})
+
+		actual, err := ParseBlueprintStrict([]byte(`{"blueprintApi": "test/custom"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "strict", actual.GetID())
+	})
+	t.Run("should not fall back to lenient parser", func(t *testing.T) {
+		const api BlueprintApi = "test/lenient"
+		defer unregisterBlueprintParser(api)
+		RegisterBlueprintParser(api, func([]byte) (Blueprint, error) {
+			return &BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: api}}, nil
+		})
+
+		_, err := ParseBlueprintStrict([]byte(`{"blueprintApi": "test/lenient"}`))
+
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+	})
+	t.Run("should panic for already registered API version", func(t *testing.T) {
+		assert.PanicsWithValue(t, `strict blueprint parser for API version "v1" is already registered`, func() {
+			RegisterStrictBlueprintParser(V1, parseBlueprintV1Strict)
+		})
+	})
+	t.Run("should panic for nil parser", func(t *testing.T) {
+		assert.PanicsWithValue(t, `strict blueprint parser for API version "test/nil" must not be nil`, func() {
+			RegisterStrictBlueprintParser("test/nil", nil)
+		})
+	})
+}
+
+func TestParseWithRegistry(t *testing.T) {
+	t.Run("should parse test/empty blueprint with no-op parser", func(t *testing.T) {
+		actual, err := ParseWithRegistry([]byte(`{"blueprintApi": "test/empty", "blueprintId": "ignored"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, &BlueprintTestEmpty{GeneralBlueprint{API: TestEmpty}}, actual)
+	})
+	t.Run("should fail for populated test/empty blueprint", func(t *testing.T) {
+		rawBlueprint := []byte(`{
+			"blueprintApi": "test/empty",
+			"dogus": [{"name": "official/nginx", "version": "1.2.3-4"}],
+			"registryConfig": {"_global": {"fqdn": "ces.example.com"}}
+		}`)
+
+		actual, err := ParseWithRegistry(rawBlueprint)
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorContains(t, err, `blueprint with API version "test/empty" must be empty but contains dogus, registryConfig`)
+	})
+	t.Run("should fail for malformed test/empty blueprint", func(t *testing.T) {
+		_, err := ParseWithRegistry([]byte(`{"blueprintApi": "test/empty", "dogus": {}}`))
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+	})
+	t.Run("should fail for unregistered API version", func(t *testing.T) {
+		_, err := ParseWithRegistry([]byte(`{"blueprintApi": "v99"}`))
+
+		assert.ErrorContains(t, err, `unsupported blueprint API version "v99"`)
+	})
+}
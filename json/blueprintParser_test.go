@@ -0,0 +1,308 @@
+package json
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlueprintTyped(t *testing.T) {
+	t.Run("should parse v1 blueprint", func(t *testing.T) {
+		rawBlueprint := []byte(`{
+			"blueprintApi": "v1",
+			"blueprintId": "my-blueprint",
+			"cesappVersion": "7.0.0-1",
+			"dogus": [{"name": "official/nginx", "version": "1.2.3-4", "targetState": "present"}],
+			"packages": [{"name": "cesapp", "version": "7.0.0-1", "targetState": "absent"}]
+		}`)
+
+		actual, err := ParseBlueprintTyped(rawBlueprint)
+
+		require.NoError(t, err)
+		require.IsType(t, &BlueprintV1{}, actual)
+		blueprint := actual.(*BlueprintV1)
+		assert.Equal(t, V1, blueprint.API)
+		assert.Equal(t, "my-blueprint", blueprint.ID)
+		assert.Equal(t, "7.0.0-1", blueprint.CesAppVersion)
+		assert.Equal(t, []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStatePresent}}, blueprint.Dogus)
+		assert.Equal(t, []TargetPackage{{Name: "cesapp", Version: "7.0.0-1", TargetState: TargetStateAbsent}}, blueprint.Packages)
+	})
+	t.Run("should parse test/empty blueprint", func(t *testing.T) {
+		actual, err := ParseBlueprintTyped([]byte(`{"blueprintApi": "test/empty"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, &BlueprintTestEmpty{GeneralBlueprint{API: TestEmpty}}, actual)
+	})
+	t.Run("should fail for unsupported API version", func(t *testing.T) {
+		actual, err := ParseBlueprintTyped([]byte(`{"blueprintApi": "v99"}`))
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+		assert.ErrorContains(t, err, `unsupported blueprint API version "v99"`)
+	})
+	t.Run("should fail for missing API version", func(t *testing.T) {
+		actual, err := ParseBlueprintTyped([]byte(`{"blueprintId": "my-blueprint"}`))
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, ErrMissingAPIVersion)
+	})
+	t.Run("should fail for invalid JSON", func(t *testing.T) {
+		actual, err := ParseBlueprintTyped([]byte(`{`))
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "could not parse blueprint")
+	})
+	t.Run("should fail for invalid v1 content", func(t *testing.T) {
+		actual, err := ParseBlueprintTyped([]byte(`{"blueprintApi": "v1", "dogus": "nginx"}`))
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, `could not parse blueprint with API version "v1"`)
+	})
+}
+
+func TestBlueprint(t *testing.T) {
+	t.Run("should be implemented by all blueprint versions", func(t *testing.T) {
+		var _ Blueprint = BlueprintV1{}
+		var _ Blueprint = &BlueprintV1{}
+		var _ Blueprint = BlueprintV2{}
+		var _ Blueprint = &BlueprintTestEmpty{}
+	})
+	t.Run("should handle parsed blueprints uniformly", func(t *testing.T) {
+		rawBlueprints := map[string]BlueprintApi{
+			`{"blueprintApi": "v1", "blueprintId": "my-blueprint", "dogus": [{"name": "official/nginx", "version": "1.2.3-4"}]}`: V1,
+			`{"blueprintApi": "test/empty"}`: TestEmpty,
+		}
+		for rawBlueprint, api := range rawBlueprints {
+			actual, err := ParseBlueprintTyped([]byte(rawBlueprint))
+
+			require.NoError(t, err)
+			assert.Equal(t, api, actual.GetAPI())
+			if api == V1 {
+				assert.Equal(t, "my-blueprint", actual.GetID())
+				assert.Equal(t, []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}}, actual.GetDogus())
+				assert.ErrorContains(t, actual.Validate(), "cesapp version must not be empty")
+			} else {
+				assert.Empty(t, actual.GetID())
+				assert.Empty(t, actual.GetDogus())
+				assert.NoError(t, actual.Validate())
+			}
+		}
+	})
+	t.Run("should fail validation of test/empty blueprint with other API version", func(t *testing.T) {
+		err := BlueprintTestEmpty{GeneralBlueprint{API: V1}}.Validate()
+
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+	})
+}
+
+func TestParseBlueprintStrict(t *testing.T) {
+	t.Run("should parse v1 blueprint with known fields", func(t *testing.T) {
+		rawBlueprint := []byte(`{
+			"blueprintApi": "v1",
+			"blueprintId": "my-blueprint",
+			"cesappVersion": "7.0.0-1",
+			"dogus": [{"name": "official/nginx", "version": "1.2.3-4"}],
+			"registryConfig": {"_global": {"fqdn": "ces.example.com"}}
+		}`)
+
+		actual, err := ParseBlueprintStrict(rawBlueprint)
+
+		require.NoError(t, err)
+		require.IsType(t, &BlueprintV1{}, actual)
+		assert.Equal(t, "my-blueprint", actual.(*BlueprintV1).ID)
+	})
+	t.Run("should fail for unknown top-level field", func(t *testing.T) {
+		rawBlueprint := []byte(`{"blueprintApi": "v1", "blueprintId": "my-blueprint", "dugos": []}`)
+
+		actual, err := ParseBlueprintStrict(rawBlueprint)
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, `unknown field "dugos"`)
+	})
+	t.Run("should fail for unknown nested field", func(t *testing.T) {
+		rawBlueprint := []byte(`{"blueprintApi": "v1", "dogus": [{"name": "official/nginx", "verison": "1.2.3-4"}]}`)
+
+		_, err := ParseBlueprintStrict(rawBlueprint)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `unknown field "verison"`)
+	})
+	t.Run("should fail for content in test/empty blueprint", func(t *testing.T) {
+		_, err := ParseBlueprintStrict([]byte(`{"blueprintApi": "test/empty", "blueprintId": "my-blueprint"}`))
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `unknown field "blueprintId"`)
+	})
+	t.Run("should fail for unsupported API version", func(t *testing.T) {
+		_, err := ParseBlueprintStrict([]byte(`{"blueprintApi": "v99"}`))
+
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+	})
+	t.Run("lenient parser should ignore unknown fields", func(t *testing.T) {
+		rawBlueprint := []byte(`{"blueprintApi": "v1", "blueprintId": "my-blueprint", "dugos": []}`)
+
+		actual, err := ParseBlueprintTyped(rawBlueprint)
+
+		require.NoError(t, err)
+		assert.Equal(t, "my-blueprint", actual.(*BlueprintV1).ID)
+	})
+}
+
+func TestParseBlueprints(t *testing.T) {
+	t.Run("should parse array in order", func(t *testing.T) {
+		rawBlueprints := []byte(`
+			[
+				{"blueprintApi": "v1", "blueprintId": "first"},
+				{"blueprintApi": "test/empty"},
+				{"blueprintApi": "v1", "blueprintId": "third"}
+			]`)
+
+		actual, err := ParseBlueprints(rawBlueprints)
+
+		require.NoError(t, err)
+		assert.Equal(t, []GeneralBlueprint{{API: V1}, {API: TestEmpty}, {API: V1}}, actual)
+	})
+	t.Run("should parse single object", func(t *testing.T) {
+		actual, err := ParseBlueprints([]byte(` {"blueprintApi": "v1"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []GeneralBlueprint{{API: V1}}, actual)
+	})
+	t.Run("should parse empty array", func(t *testing.T) {
+		actual, err := ParseBlueprints([]byte(`[]`))
+
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+	t.Run("should name index of malformed element", func(t *testing.T) {
+		actual, err := ParseBlueprints([]byte(`[{"blueprintApi": "v1"}, {"blueprintApi": 1}]`))
+
+		require.Error(t, err)
+		assert.Nil(t, actual)
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "could not parse blueprint at index 1")
+	})
+	t.Run("should fail for malformed array", func(t *testing.T) {
+		_, err := ParseBlueprints([]byte(`[{"blueprintApi": "v1"},`))
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "could not parse blueprint array")
+	})
+	t.Run("should fail for malformed single object", func(t *testing.T) {
+		_, err := ParseBlueprints([]byte(`{`))
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+	})
+}
+
+// blockingReader returns the given data and then blocks until unblock is closed.
+type blockingReader struct {
+	data    io.Reader
+	unblock chan struct{}
+}
+
+func (br *blockingReader) Read(p []byte) (int, error) {
+	n, err := br.data.Read(p)
+	if err == io.EOF {
+		<-br.unblock
+	}
+	return n, err
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, assert.AnError
+}
+
+// countingReader is an endless stream of spaces which counts the bytes read from it.
+type countingReader struct {
+	read int
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	cr.read += len(p)
+	return len(p), nil
+}
+
+func TestParseBlueprintContext(t *testing.T) {
+	t.Run("should parse blueprint", func(t *testing.T) {
+		actual, err := ParseBlueprintContext(context.Background(), strings.NewReader(`{"blueprintApi": "v1"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, GeneralBlueprint{API: V1}, actual)
+	})
+	t.Run("should fail for invalid blueprint", func(t *testing.T) {
+		_, err := ParseBlueprintContext(context.Background(), strings.NewReader(`{`))
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+	})
+	t.Run("should fail for read error", func(t *testing.T) {
+		_, err := ParseBlueprintContext(context.Background(), failingReader{})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not read blueprint")
+	})
+	t.Run("should stop reading after the maximum size", func(t *testing.T) {
+		reader := &countingReader{}
+
+		_, err := ParseBlueprintContext(context.Background(), reader)
+
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+		assert.ErrorContains(t, err, "blueprint has more than 10485760 bytes")
+		assert.LessOrEqual(t, reader.read, defaultMaxBytes+1)
+	})
+	t.Run("should parse blueprint of maximum size", func(t *testing.T) {
+		rawBlueprint := `{"blueprintApi": "v1"}`
+		rawBlueprint += strings.Repeat(" ", defaultMaxBytes-len(rawBlueprint))
+
+		actual, err := ParseBlueprintContext(context.Background(), strings.NewReader(rawBlueprint))
+
+		require.NoError(t, err)
+		assert.Equal(t, GeneralBlueprint{API: V1}, actual)
+	})
+	t.Run("should fail for already cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := ParseBlueprintContext(ctx, strings.NewReader(`{"blueprintApi": "v1"}`))
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("should return promptly when cancelled mid-read", func(t *testing.T) {
+		reader := &blockingReader{data: strings.NewReader(`{"blueprintApi": `), unblock: make(chan struct{})}
+		defer close(reader.unblock)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := ParseBlueprintContext(ctx, reader)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("should respect deadline", func(t *testing.T) {
+		reader := &blockingReader{data: strings.NewReader(`{"blueprintApi": `), unblock: make(chan struct{})}
+		defer close(reader.unblock)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := ParseBlueprintContext(ctx, reader)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,132 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// namedListFields contains the JSON fields of a blueprint whose entries are identified by their name.
+var namedListFields = []string{"dogus", "packages"}
+
+// ApplyMergePatch applies the given JSON merge patch (RFC 7386) to the original blueprint JSON and parses and
+// validates the result. As defined by the RFC, objects are merged recursively, null values delete the corresponding
+// field and all other values, including arrays, replace the original value.
+//
+// Because dogus and packages are arrays, they can additionally be patched by name. If the patch contains "dogus" or
+// "packages" as an object keyed by the name, every entry is merged into the entry with the same name, a null value
+// deletes the entry and entries with unknown names are appended in the order of their names:
+//
+//	{"dogus": {"official/nginx": {"version": "1.3.0-1"}, "official/redmine": null}}
+//
+// Packages are matched by name only, regardless of their package manager. A patch entry therefore applies to every
+// package with its name; to patch the package of a single package manager, replace the whole "packages" array.
+func ApplyMergePatch(original []byte, patch []byte) (BlueprintV1, error) {
+	originalObject, err := unmarshalJSONObject(original)
+	if err != nil {
+		return BlueprintV1{}, fmt.Errorf("could not parse original blueprint: %w", err)
+	}
+	patchObject, err := unmarshalJSONObject(patch)
+	if err != nil {
+		return BlueprintV1{}, fmt.Errorf("could not parse merge patch: %w", err)
+	}
+
+	for _, field := range namedListFields {
+		entries, ok := patchObject[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		originalObject[field] = mergeNamedList(originalObject[field], entries)
+		delete(patchObject, field)
+	}
+	patched, err := json.Marshal(mergePatch(originalObject, patchObject))
+	if err != nil {
+		return BlueprintV1{}, fmt.Errorf("could not marshal patched blueprint: %w", err)
+	}
+
+	parsed, err := ParseBlueprintTyped(patched)
+	if err != nil {
+		return BlueprintV1{}, fmt.Errorf("could not parse patched blueprint: %w", err)
+	}
+	blueprint, ok := parsed.(*BlueprintV1)
+	if !ok {
+		return BlueprintV1{}, fmt.Errorf("patched blueprint must have API version %q", V1)
+	}
+	err = blueprint.Validate()
+	if err != nil {
+		return BlueprintV1{}, fmt.Errorf("patched blueprint is invalid: %w", err)
+	}
+
+	return *blueprint, nil
+}
+
+func unmarshalJSONObject(raw []byte) (map[string]interface{}, error) {
+	var document interface{}
+	err := json.Unmarshal(raw, &document)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a JSON object", ErrInvalidJSON)
+	}
+	return object, nil
+}
+
+// mergePatch merges the patch into the target as defined by RFC 7386. The target may be modified.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// mergeNamedList merges the patch entries keyed by name into the list of named entries, see ApplyMergePatch.
+func mergeNamedList(list interface{}, patchEntries map[string]interface{}) []interface{} {
+	originalEntries, _ := list.([]interface{})
+
+	result := make([]interface{}, 0, len(originalEntries)+len(patchEntries))
+	matched := map[string]bool{}
+	for _, entry := range originalEntries {
+		entryObject, _ := entry.(map[string]interface{})
+		name, _ := entryObject["name"].(string)
+		patchEntry, ok := patchEntries[name]
+		if !ok {
+			result = append(result, entry)
+			continue
+		}
+
+		matched[name] = true
+		if patchEntry != nil {
+			result = append(result, mergePatch(entry, patchEntry))
+		}
+	}
+
+	names := make([]string, 0, len(patchEntries))
+	for name := range patchEntries {
+		if !matched[name] && patchEntries[name] != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result = append(result, mergePatch(map[string]interface{}{"name": name}, patchEntries[name]))
+	}
+
+	return result
+}
@@ -0,0 +1,115 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var originalPatchBlueprint = []byte(`{
+	"blueprintApi": "v1",
+	"blueprintId": "my-blueprint",
+	"cesappVersion": "7.0.0-1",
+	"dogus": [
+		{"name": "official/nginx", "version": "1.2.3-4"},
+		{"name": "official/redmine", "version": "5.0.0-1"}
+	],
+	"packages": [{"name": "cesapp", "version": "7.0.0-1"}],
+	"registryConfig": {"_global": {"fqdn": "ces.example.com", "admin_group": "admins"}}
+}`)
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Run("should merge objects and replace other values", func(t *testing.T) {
+		patch := []byte(`{
+			"cesappVersion": "7.1.0-1",
+			"registryConfig": {"_global": {"fqdn": "prod.example.com", "admin_group": null}},
+			"packages": [{"name": "ces-commons", "version": "1.0.0-1"}]
+		}`)
+
+		actual, err := ApplyMergePatch(originalPatchBlueprint, patch)
+
+		require.NoError(t, err)
+		assert.Equal(t, "7.1.0-1", actual.CesAppVersion)
+		assert.Equal(t, RegistryConfig{"_global": {"fqdn": "prod.example.com"}}, actual.RegistryConfig)
+		assert.Equal(t, []TargetPackage{{Name: "ces-commons", Version: "1.0.0-1"}}, actual.Packages)
+		assert.Len(t, actual.Dogus, 2)
+	})
+	t.Run("should patch dogus by name", func(t *testing.T) {
+		patch := []byte(`{"dogus": {
+			"official/nginx": {"version": "1.3.0-1"},
+			"official/redmine": null,
+			"official/scm": {"version": "2.0.0-1"}
+		}}`)
+
+		actual, err := ApplyMergePatch(originalPatchBlueprint, patch)
+
+		require.NoError(t, err)
+		assert.Equal(t, []TargetDogu{
+			{Name: "official/nginx", Version: "1.3.0-1"},
+			{Name: "official/scm", Version: "2.0.0-1"},
+		}, actual.Dogus)
+	})
+	t.Run("should patch packages by name", func(t *testing.T) {
+		patch := []byte(`{"packages": {"cesapp": {"targetState": "absent", "version": null}}}`)
+
+		actual, err := ApplyMergePatch(originalPatchBlueprint, patch)
+
+		require.NoError(t, err)
+		assert.Equal(t, []TargetPackage{{Name: "cesapp", TargetState: TargetStateAbsent}}, actual.Packages)
+	})
+	t.Run("should patch packages of every package manager by name", func(t *testing.T) {
+		original := []byte(`{
+			"blueprintApi": "v1",
+			"blueprintId": "my-blueprint",
+			"cesappVersion": "7.0.0-1",
+			"packages": [
+				{"name": "curl", "version": "7.0.0-1", "packageManager": "apt"},
+				{"name": "curl", "version": "7.0.0-1", "packageManager": "yum"}
+			]
+		}`)
+		patch := []byte(`{"packages": {"curl": {"version": "7.1.0-1"}}}`)
+
+		actual, err := ApplyMergePatch(original, patch)
+
+		require.NoError(t, err)
+		assert.Equal(t, []TargetPackage{
+			{Name: "curl", Version: "7.1.0-1", PackageManager: "apt"},
+			{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+		}, actual.Packages)
+	})
+	t.Run("should delete all dogus for null", func(t *testing.T) {
+		actual, err := ApplyMergePatch(originalPatchBlueprint, []byte(`{"dogus": null}`))
+
+		require.NoError(t, err)
+		assert.Empty(t, actual.Dogus)
+	})
+	t.Run("should fail for invalid original", func(t *testing.T) {
+		_, err := ApplyMergePatch([]byte(`{`), []byte(`{}`))
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "could not parse original blueprint")
+	})
+	t.Run("should fail for patch which is not an object", func(t *testing.T) {
+		_, err := ApplyMergePatch(originalPatchBlueprint, []byte(`[]`))
+
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "could not parse merge patch")
+	})
+	t.Run("should fail for invalid result", func(t *testing.T) {
+		_, err := ApplyMergePatch(originalPatchBlueprint, []byte(`{"dogus": {"official/nginx": {"version": null}}}`))
+
+		assert.ErrorContains(t, err, "patched blueprint is invalid")
+		assert.ErrorContains(t, err, `version of "official/nginx" must not be empty`)
+	})
+	t.Run("should fail for unparsable result", func(t *testing.T) {
+		_, err := ApplyMergePatch(originalPatchBlueprint, []byte(`{"dogus": {"official/nginx": {"targetState": "gone"}}}`))
+
+		assert.ErrorContains(t, err, "could not parse patched blueprint")
+	})
+	t.Run("should fail for other API version", func(t *testing.T) {
+		_, err := ApplyMergePatch([]byte(`{"blueprintApi": "test/empty"}`), []byte(`{}`))
+
+		assert.ErrorContains(t, err, `patched blueprint must have API version "v1"`)
+	})
+}
@@ -0,0 +1,47 @@
+package json
+
+// Reconcile compares the dogus of the blueprint with the dogus currently installed in a CES instance and computes the
+// necessary actions. Dogus are matched by their full namespaced name, the target state of the current dogus is not
+// interpreted.
+//
+//   - toInstall contains the present dogus of the blueprint which are not installed.
+//   - toUpgrade contains the present dogus of the blueprint which are installed in a different version.
+//   - toRemove contains the absent dogus of the blueprint which are installed.
+//   - ignored contains the installed dogus which are not mentioned in the blueprint, see IgnoredDogus.
+//
+// The dogus to install, upgrade and remove are taken from the blueprint. The ignored dogus are taken from the current
+// dogus and marked with TargetStateIgnore, so that every installed dogu is either acted upon or explicitly left as
+// it is.
+func (b BlueprintV1) Reconcile(current []TargetDogu) (toInstall, toUpgrade, toRemove, ignored []TargetDogu) {
+	currentByName := make(map[string]TargetDogu, len(current))
+	for _, dogu := range current {
+		currentByName[dogu.Name] = dogu
+	}
+
+	for _, dogu := range b.Dogus {
+		installed, isInstalled := currentByName[dogu.Name]
+		switch {
+		case dogu.TargetState == TargetStatePresent && !isInstalled:
+			toInstall = append(toInstall, dogu)
+		case dogu.TargetState == TargetStatePresent && installed.Version != dogu.Version:
+			toUpgrade = append(toUpgrade, dogu)
+		case dogu.TargetState == TargetStateAbsent && isInstalled:
+			toRemove = append(toRemove, dogu)
+		}
+	}
+
+	return toInstall, toUpgrade, toRemove, b.IgnoredDogus(current)
+}
+
+// IgnoredDogus returns the currently installed dogus which are not mentioned in the blueprint. They are marked with
+// TargetStateIgnore because applying the blueprint must leave them as they are.
+func (b BlueprintV1) IgnoredDogus(current []TargetDogu) []TargetDogu {
+	var ignored []TargetDogu
+	for _, dogu := range current {
+		if _, mentioned := b.FindDogu(dogu.Name); !mentioned {
+			dogu.TargetState = TargetStateIgnore
+			ignored = append(ignored, dogu)
+		}
+	}
+	return ignored
+}
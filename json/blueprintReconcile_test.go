@@ -0,0 +1,58 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintV1_Reconcile(t *testing.T) {
+	sut := BlueprintV1{Dogus: []TargetDogu{
+		{Name: "official/nginx", Version: "1.2.3-4"},
+		{Name: "official/redmine", Version: "5.1.0-1"},
+		{Name: "official/scm", Version: "2.0.0-1"},
+		{Name: "official/ldap", TargetState: TargetStateAbsent},
+		{Name: "official/postfix", TargetState: TargetStateAbsent},
+	}}
+	current := []TargetDogu{
+		{Name: "official/nginx", Version: "1.2.3-4"},
+		{Name: "official/redmine", Version: "5.0.0-1"},
+		{Name: "official/ldap", Version: "2.0.0-1"},
+		{Name: "official/cas", Version: "7.0.0-1"},
+	}
+
+	toInstall, toUpgrade, toRemove, ignored := sut.Reconcile(current)
+
+	assert.Equal(t, []TargetDogu{{Name: "official/scm", Version: "2.0.0-1"}}, toInstall)
+	assert.Equal(t, []TargetDogu{{Name: "official/redmine", Version: "5.1.0-1"}}, toUpgrade)
+	assert.Equal(t, []TargetDogu{{Name: "official/ldap", TargetState: TargetStateAbsent}}, toRemove)
+	assert.Equal(t, []TargetDogu{{Name: "official/cas", Version: "7.0.0-1", TargetState: TargetStateIgnore}}, ignored)
+}
+
+func TestBlueprintV1_Reconcile_nothingToDo(t *testing.T) {
+	sut := BlueprintV1{Dogus: []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}}}
+
+	toInstall, toUpgrade, toRemove, ignored := sut.Reconcile([]TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}})
+
+	assert.Empty(t, toInstall)
+	assert.Empty(t, toUpgrade)
+	assert.Empty(t, toRemove)
+	assert.Empty(t, ignored)
+}
+
+func TestBlueprintV1_IgnoredDogus(t *testing.T) {
+	sut := BlueprintV1{Dogus: []TargetDogu{
+		{Name: "official/nginx", Version: "1.2.3-4"},
+		{Name: "official/ldap", TargetState: TargetStateAbsent},
+	}}
+	current := []TargetDogu{
+		{Name: "official/nginx", Version: "1.2.3-4"},
+		{Name: "official/ldap", Version: "2.0.0-1"},
+		{Name: "official/cas", Version: "7.0.0-1"},
+	}
+
+	actual := sut.IgnoredDogus(current)
+
+	assert.Equal(t, []TargetDogu{{Name: "official/cas", Version: "7.0.0-1", TargetState: TargetStateIgnore}}, actual)
+	assert.Equal(t, TargetStatePresent, current[2].TargetState)
+}
@@ -0,0 +1,142 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	targetStateType             = reflect.TypeOf(TargetState(0))
+	registryConfigType          = reflect.TypeOf(RegistryConfig{})
+	encryptedRegistryConfigType = reflect.TypeOf(EncryptedRegistryConfig{})
+)
+
+// schemaRequiredFields contains the JSON field names which must be present and not empty per struct type, as
+// documented on the struct fields.
+var schemaRequiredFields = map[reflect.Type][]string{
+	reflect.TypeOf(BlueprintV1{}):   {"blueprintApi", "blueprintId", "cesappVersion"},
+	reflect.TypeOf(TargetDogu{}):    {"name"},
+	reflect.TypeOf(TargetPackage{}): {"name"},
+}
+
+// BlueprintV1Schema returns a JSON Schema (draft 2020-12) describing the structure of V1 blueprints. The schema is
+// generated from the Go structs of this package so that it matches the parsing behaviour. It contains the required
+// fields, the valid target states and requires a version for every dogu and package that is not absent.
+func BlueprintV1Schema() ([]byte, error) {
+	schema, err := schemaForType(reflect.TypeOf(BlueprintV1{}))
+	if err != nil {
+		return nil, fmt.Errorf("could not generate JSON schema for blueprint API version %q: %w", V1, err)
+	}
+
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = "Cloudogu EcoSystem blueprint " + string(V1)
+	schema["properties"].(map[string]interface{})["blueprintApi"] = map[string]interface{}{"const": V1}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func schemaForType(t reflect.Type) (map[string]interface{}, error) {
+	switch {
+	case t == targetStateType:
+		return map[string]interface{}{"type": "string", "enum": []string{toString[TargetStatePresent], toString[TargetStateAbsent]}}, nil
+	case t == registryConfigType, t == encryptedRegistryConfigType:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"type": "object"},
+		}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := schemaForType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case reflect.Slice:
+		items, err := schemaForType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case reflect.Struct:
+		return schemaForStruct(t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func schemaForStruct(t reflect.Type) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	err := addStructProperties(t, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	required := schemaRequiredFields[t]
+	if len(required) > 0 {
+		schema["required"] = required
+		for _, fieldName := range required {
+			property, ok := properties[fieldName].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("required field %q does not exist in %s", fieldName, t)
+			}
+			if property["type"] == "string" {
+				property["minLength"] = 1
+			}
+		}
+	}
+
+	if _, hasTargetState := properties["targetState"]; hasTargetState {
+		schema["if"] = map[string]interface{}{
+			"properties": map[string]interface{}{"targetState": map[string]interface{}{"const": toString[TargetStateAbsent]}},
+			"required":   []string{"targetState"},
+		}
+		schema["else"] = map[string]interface{}{
+			"properties": map[string]interface{}{"version": map[string]interface{}{"minLength": 1}},
+			"required":   []string{"version"},
+		}
+	}
+
+	return schema, nil
+}
+
+func addStructProperties(t reflect.Type, properties map[string]interface{}) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			err := addStructProperties(field.Type, properties)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		fieldName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || fieldName == "-" || fieldName == "" {
+			continue
+		}
+
+		property, err := schemaForType(field.Type)
+		if err != nil {
+			return fmt.Errorf("could not generate schema for field %s of %s: %w", field.Name, t, err)
+		}
+		properties[fieldName] = property
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileBlueprintV1Schema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+
+	rawSchema, err := BlueprintV1Schema()
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource("blueprint-v1.json", bytes.NewReader(rawSchema)))
+	schema, err := compiler.Compile("blueprint-v1.json")
+	require.NoError(t, err)
+
+	return schema
+}
+
+func validateAgainstSchema(t *testing.T, schema *jsonschema.Schema, rawBlueprint string) error {
+	t.Helper()
+
+	var blueprint interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawBlueprint), &blueprint))
+	return schema.Validate(blueprint)
+}
+
+func TestBlueprintV1Schema(t *testing.T) {
+	schema := compileBlueprintV1Schema(t)
+
+	t.Run("should accept valid blueprint", func(t *testing.T) {
+		rawBlueprint := `{
+			"blueprintApi": "v1",
+			"blueprintId": "my-blueprint",
+			"cesappVersion": "7.0.0-1",
+			"dogus": [
+				{"name": "official/nginx", "version": "1.2.3-4", "targetState": "present"},
+				{"name": "official/ldap", "version": "2.0.0-1"},
+				{"name": "official/redmine", "targetState": "absent"}
+			],
+			"packages": [{"name": "cesapp", "version": "7.0.0-1"}],
+			"registryConfig": {"_global": {"fqdn": "ces.example.com"}},
+			"registryConfigAbsent": ["redmine/theme"],
+			"registryConfigEncrypted": {"redmine": {"secret": "s3cr3t"}}
+		}`
+
+		err := validateAgainstSchema(t, schema, rawBlueprint)
+
+		assert.NoError(t, err)
+	})
+	tests := []struct {
+		name         string
+		rawBlueprint string
+	}{
+		{"wrong API version", `{"blueprintApi": "v2", "blueprintId": "a", "cesappVersion": "1.0.0-1"}`},
+		{"missing blueprint ID", `{"blueprintApi": "v1", "cesappVersion": "1.0.0-1"}`},
+		{"empty cesapp version", `{"blueprintApi": "v1", "blueprintId": "a", "cesappVersion": ""}`},
+		{"unknown field", `{"blueprintApi": "v1", "blueprintId": "a", "cesappVersion": "1.0.0-1", "dugos": []}`},
+		{"invalid target state", `{"blueprintApi": "v1", "blueprintId": "a", "cesappVersion": "1.0.0-1", "dogus": [{"name": "official/nginx", "version": "1.0.0-1", "targetState": "removed"}]}`},
+		{"internal target state", `{"blueprintApi": "v1", "blueprintId": "a", "cesappVersion": "1.0.0-1", "dogus": [{"name": "official/nginx", "version": "1.0.0-1", "targetState": "ignore"}]}`},
+		{"missing version of present dogu", `{"blueprintApi": "v1", "blueprintId": "a", "cesappVersion": "1.0.0-1", "dogus": [{"name": "official/nginx"}]}`},
+		{"missing package name", `{"blueprintApi": "v1", "blueprintId": "a", "cesappVersion": "1.0.0-1", "packages": [{"version": "1.0.0-1"}]}`},
+		{"invalid registry config", `{"blueprintApi": "v1", "blueprintId": "a", "cesappVersion": "1.0.0-1", "registryConfig": {"_global": "fqdn"}}`},
+	}
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			err := validateAgainstSchema(t, schema, tt.rawBlueprint)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+)
+
+// V2 is the preliminary version 2 API identifier of Cloudogu EcoSystem blueprint mechanism which groups the parts of a
+// blueprint into components and config. It is not yet supported by the blueprint parsers.
+const V2 BlueprintApi = "v2"
+
+// BlueprintV2 describes the parts of a Cloudogu EcoSystem that should be absent or present like BlueprintV1, but
+// groups them into components and config.
+type BlueprintV2 struct {
+	GeneralBlueprint `yaml:",inline"`
+	// ID is the unique name of the set over all parts. This blueprint ID should be used to distinguish from similar
+	// blueprints between humans in an easy way. Must not be empty.
+	ID string `json:"blueprintId" yaml:"blueprintId"`
+	// CesAppVersion defines the exact version of the cesapp that should be present in the CES instance after which this
+	// blueprint was applied. Must not be empty.
+	CesAppVersion string `json:"cesappVersion" yaml:"cesappVersion"`
+	// Components contains the dogus and packages which should be present or absent in the CES instance after which this
+	// blueprint was applied. Must not be omitted.
+	Components ComponentsV2 `json:"components" yaml:"components"`
+	// Config contains the registry config which should be set or removed when this blueprint is applied. Must not
+	// be omitted.
+	Config ConfigV2 `json:"config" yaml:"config"`
+	// Metadata contains free-form annotations of the blueprint, f. i. its author or a ticket link. It is not interpreted
+	// when the blueprint is applied. Optional.
+	Metadata map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// ComponentsV2 groups the dogus and packages of a BlueprintV2. In contrast to BlueprintV1 both lists are always
+// serialized, even if they are empty.
+type ComponentsV2 struct {
+	// Dogus contains a set of exact dogu versions which should be present or absent in the CES instance.
+	Dogus []TargetDogu `json:"dogus" yaml:"dogus"`
+	// Packages contains a set of exact package versions which should be present or absent in the CES instance.
+	Packages []TargetPackage `json:"packages" yaml:"packages"`
+}
+
+// ConfigV2 groups the registry config of a BlueprintV2.
+type ConfigV2 struct {
+	// Registry is used to configure registry entries on blueprint upgrades.
+	Registry RegistryConfig `json:"registry,omitempty" yaml:"registry,omitempty"`
+	// RegistryAbsent is used to remove registry entries on blueprint upgrades.
+	RegistryAbsent []string `json:"registryAbsent,omitempty" yaml:"registryAbsent,omitempty"`
+	// RegistryEncrypted is used to configure encrypted registry entries on blueprint upgrades.
+	RegistryEncrypted EncryptedRegistryConfig `json:"registryEncrypted,omitempty" yaml:"registryEncrypted,omitempty"`
+}
+
+// GetID returns the blueprint ID.
+func (b BlueprintV2) GetID() string {
+	return b.ID
+}
+
+// GetDogus returns the dogus of the blueprint.
+func (b BlueprintV2) GetDogus() []TargetDogu {
+	return b.Components.Dogus
+}
+
+// Validate checks that the blueprint has the API version V2 and that its content fulfills the invariants of a
+// BlueprintV1, see BlueprintV1.Validate.
+func (b BlueprintV2) Validate() error {
+	var errs []error
+
+	if b.API != V2 {
+		errs = append(errs, fmt.Errorf("%w %q, expected %q", ErrUnsupportedAPIVersion, b.API, V2))
+	}
+	content := BlueprintV1{
+		GeneralBlueprint:        GeneralBlueprint{API: V1},
+		ID:                      b.ID,
+		CesAppVersion:           b.CesAppVersion,
+		Dogus:                   b.Components.Dogus,
+		Packages:                b.Components.Packages,
+		RegistryConfig:          b.Config.Registry,
+		RegistryConfigAbsent:    b.Config.RegistryAbsent,
+		RegistryConfigEncrypted: b.Config.RegistryEncrypted,
+	}
+	errs = append(errs, content.Validate())
+
+	return errors.Join(errs...)
+}
+
+// UpgradeV1ToV2 converts the given V1 blueprint into a V2 blueprint. Dogus, packages, registry config and metadata are
+// preserved, the dogu and package lists default to empty lists. The result shares no slices or maps with the given
+// blueprint. An error is returned if the given blueprint does not have the API version V1.
+func UpgradeV1ToV2(v1 BlueprintV1) (BlueprintV2, error) {
+	if v1.API != V1 {
+		return BlueprintV2{}, fmt.Errorf("cannot upgrade blueprint %q with API version %q to %q: expected API version %q",
+			v1.ID, v1.API, V2, V1)
+	}
+
+	copied := v1.DeepCopy()
+	v2 := BlueprintV2{
+		GeneralBlueprint: GeneralBlueprint{API: V2},
+		ID:               copied.ID,
+		CesAppVersion:    copied.CesAppVersion,
+		Components: ComponentsV2{
+			Dogus:    copied.Dogus,
+			Packages: copied.Packages,
+		},
+		Config: ConfigV2{
+			Registry:          copied.RegistryConfig,
+			RegistryAbsent:    copied.RegistryConfigAbsent,
+			RegistryEncrypted: copied.RegistryConfigEncrypted,
+		},
+		Metadata: copied.Metadata,
+	}
+	if v2.Components.Dogus == nil {
+		v2.Components.Dogus = []TargetDogu{}
+	}
+	if v2.Components.Packages == nil {
+		v2.Components.Packages = []TargetPackage{}
+	}
+
+	return v2, nil
+}
@@ -0,0 +1,99 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeV1ToV2(t *testing.T) {
+	t.Run("should preserve all parts", func(t *testing.T) {
+		v1 := BlueprintV1{
+			GeneralBlueprint:        GeneralBlueprint{API: V1},
+			ID:                      "my-blueprint",
+			CesAppVersion:           "7.0.0-1",
+			Dogus:                   []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}},
+			Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com"}},
+			RegistryConfigAbsent:    []string{"redmine/theme"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+			Metadata:                map[string]string{"author": "jane"},
+		}
+
+		actual, err := UpgradeV1ToV2(v1)
+
+		require.NoError(t, err)
+		expected := BlueprintV2{
+			GeneralBlueprint: GeneralBlueprint{API: V2},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Components: ComponentsV2{
+				Dogus:    []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}},
+				Packages: []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}},
+			},
+			Config: ConfigV2{
+				Registry:          RegistryConfig{"_global": {"fqdn": "ces.example.com"}},
+				RegistryAbsent:    []string{"redmine/theme"},
+				RegistryEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+			},
+			Metadata: map[string]string{"author": "jane"},
+		}
+		assert.Equal(t, expected, actual)
+
+		actual.Components.Dogus[0].Version = "changed"
+		actual.Config.Registry["_global"]["fqdn"] = "changed"
+		assert.Equal(t, "1.2.3-4", v1.Dogus[0].Version)
+		assert.Equal(t, "ces.example.com", v1.RegistryConfig["_global"]["fqdn"])
+	})
+	t.Run("should default component lists", func(t *testing.T) {
+		actual, err := UpgradeV1ToV2(BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: V1}, ID: "my-blueprint"})
+		require.NoError(t, err)
+
+		rawBlueprint, err := json.Marshal(actual)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+			"blueprintApi": "v2",
+			"blueprintId": "my-blueprint",
+			"cesappVersion": "",
+			"components": {"dogus": [], "packages": []},
+			"config": {}
+		}`, string(rawBlueprint))
+	})
+	t.Run("should fail for other API versions", func(t *testing.T) {
+		_, err := UpgradeV1ToV2(BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: TestEmpty}, ID: "my-blueprint"})
+
+		assert.ErrorContains(t, err, `cannot upgrade blueprint "my-blueprint" with API version "test/empty" to "v2"`)
+	})
+}
+
+func TestBlueprintV2_Validate(t *testing.T) {
+	t.Run("should succeed for upgraded valid blueprint", func(t *testing.T) {
+		sut, err := UpgradeV1ToV2(createValidBlueprint())
+		require.NoError(t, err)
+
+		assert.NoError(t, sut.Validate())
+		assert.Equal(t, V2, sut.GetAPI())
+		assert.Equal(t, "my-blueprint", sut.GetID())
+		assert.Equal(t, createValidBlueprint().Dogus, sut.GetDogus())
+	})
+	t.Run("should fail for invalid content and API version", func(t *testing.T) {
+		sut := BlueprintV2{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Components:       ComponentsV2{Dogus: []TargetDogu{{Name: "official/nginx"}}},
+			Config:           ConfigV2{RegistryAbsent: []string{""}},
+		}
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+		assert.ErrorContains(t, err, `unsupported blueprint API version "v1", expected "v2"`)
+		assert.ErrorContains(t, err, "dogu at index 0 is invalid")
+		assert.ErrorContains(t, err, "absent registry config entry at index 0 is invalid")
+	})
+}
@@ -0,0 +1,262 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+// Validate checks the invariants documented on the fields of the blueprint and its dogus and packages. All found
+// violations are aggregated into the returned error so that every problem can be fixed at once. Validate returns nil
+// if the blueprint is valid. The returned error consists of the errors of ValidationReport, warnings are ignored.
+func (b BlueprintV1) Validate() error {
+	return validationError(b.ValidationReport())
+}
+
+// ValidateRegistryConfigValueTypes checks that all values of the registry config and the encrypted registry config
+// have one of the allowed JSON types, see RegistryConfig.ValidateValueTypes. If no types are given, only strings are
+// allowed. It is not part of Validate because the accepted types depend on the registry the blueprint is applied to.
+func (b BlueprintV1) ValidateRegistryConfigValueTypes(allowed ...RegistryValueType) error {
+	var errs []error
+
+	err := b.RegistryConfig.ValidateValueTypes(allowed...)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid registry config: %w", err))
+	}
+	err = RegistryConfig(b.RegistryConfigEncrypted).ValidateValueTypes(allowed...)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid encrypted registry config: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// duplicateAndConflictIssues reports every group of entries which identify the same item, f. i. all dogus with the
+// same name. A group with more than one entry is a duplicate and a group containing both a present and an absent
+// entry has conflicting target states, because the intended end state of the item is unclear. The issues point to the
+// first repeated and the first absent entry of the group.
+func duplicateAndConflictIssues(field, label string, entries []indexedTargetState) []ValidationIssue {
+	if len(entries) < 2 {
+		return nil
+	}
+
+	var issues []ValidationIssue
+	var indices, presentIndices, absentIndices []string
+	for _, entry := range entries {
+		index := strconv.Itoa(entry.index)
+		indices = append(indices, index)
+		switch entry.state {
+		case TargetStatePresent:
+			presentIndices = append(presentIndices, index)
+		case TargetStateAbsent:
+			absentIndices = append(absentIndices, index)
+		}
+	}
+
+	issues = append(issues, errorIssue(IssueCodeDuplicate, fmt.Sprintf("%s[%s].name", field, indices[1]),
+		fmt.Errorf("%s must not be contained more than once (index %s)", label, strings.Join(indices, ", "))))
+	if len(presentIndices) > 0 && len(absentIndices) > 0 {
+		issues = append(issues, errorIssue(IssueCodeConflictingTargetState, fmt.Sprintf("%s[%s].targetState", field, absentIndices[0]),
+			fmt.Errorf("%s must not be both present (index %s) and absent (index %s)",
+				label, strings.Join(presentIndices, ", "), strings.Join(absentIndices, ", "))))
+	}
+	return issues
+}
+
+type indexedTargetState struct {
+	index int
+	state TargetState
+}
+
+// doguDuplicateAndConflictIssues reports dogus which are contained more than once, see duplicateAndConflictIssues.
+func (b BlueprintV1) doguDuplicateAndConflictIssues() []ValidationIssue {
+	var names []string
+	entries := map[string][]indexedTargetState{}
+	for i, dogu := range b.Dogus {
+		if dogu.Name == "" {
+			continue
+		}
+		if _, seen := entries[dogu.Name]; !seen {
+			names = append(names, dogu.Name)
+		}
+		entries[dogu.Name] = append(entries[dogu.Name], indexedTargetState{index: i, state: dogu.TargetState})
+	}
+
+	var issues []ValidationIssue
+	for _, name := range names {
+		issues = append(issues, duplicateAndConflictIssues("dogus", fmt.Sprintf("dogu %q", name), entries[name])...)
+	}
+	return issues
+}
+
+// packageDuplicateAndConflictIssues reports packages which are contained more than once, see
+// duplicateAndConflictIssues. Packages of different package managers may share their name. A package without a
+// package manager applies to every package manager, so it is checked against the packages of every package manager
+// with the same name, see BlueprintV1.PackagesForManager.
+func (b BlueprintV1) packageDuplicateAndConflictIssues() []ValidationIssue {
+	var names []string
+	managers := map[string][]string{}
+	entries := map[string]map[string][]indexedTargetState{}
+	for i, pkg := range b.Packages {
+		if pkg.Name == "" {
+			continue
+		}
+		if _, seen := entries[pkg.Name]; !seen {
+			names = append(names, pkg.Name)
+			entries[pkg.Name] = map[string][]indexedTargetState{}
+		}
+		if _, seen := entries[pkg.Name][pkg.PackageManager]; !seen && pkg.PackageManager != "" {
+			managers[pkg.Name] = append(managers[pkg.Name], pkg.PackageManager)
+		}
+		entries[pkg.Name][pkg.PackageManager] = append(entries[pkg.Name][pkg.PackageManager], indexedTargetState{index: i, state: pkg.TargetState})
+	}
+
+	var issues []ValidationIssue
+	for _, name := range names {
+		if len(managers[name]) == 0 {
+			issues = append(issues, duplicateAndConflictIssues("packages", fmt.Sprintf("package %q", name), entries[name][""])...)
+			continue
+		}
+		for _, manager := range managers[name] {
+			effective := append(append([]indexedTargetState(nil), entries[name][manager]...), entries[name][""]...)
+			sort.Slice(effective, func(i, j int) bool { return effective[i].index < effective[j].index })
+			label := fmt.Sprintf("package %q", TargetPackage{Name: name, PackageManager: manager}.identity())
+			issues = append(issues, duplicateAndConflictIssues("packages", label, effective)...)
+		}
+	}
+	return issues
+}
+
+// simpleNameCollisionIssues reports every simple dogu name which is claimed by present dogus of more than one
+// namespace, f. i. "official/nginx" and "premium/nginx", because a CES cannot have two dogus with the same simple name
+// installed. The issue points to the first dogu of the second namespace. Dogus with invalid names are skipped as they
+// are already reported by their validation.
+func (b BlueprintV1) simpleNameCollisionIssues() []ValidationIssue {
+	var simpleNames []string
+	fullNames := map[string][]string{}
+	collidingIndex := map[string]int{}
+	for i, dogu := range b.Dogus {
+		if dogu.TargetState != TargetStatePresent {
+			continue
+		}
+		_, simpleName, err := dogu.SplitName()
+		if err != nil {
+			continue
+		}
+
+		if _, seen := fullNames[simpleName]; !seen {
+			simpleNames = append(simpleNames, simpleName)
+		}
+		if !containsString(fullNames[simpleName], dogu.Name) {
+			fullNames[simpleName] = append(fullNames[simpleName], dogu.Name)
+			if len(fullNames[simpleName]) == 2 {
+				collidingIndex[simpleName] = i
+			}
+		}
+	}
+
+	var issues []ValidationIssue
+	for _, simpleName := range simpleNames {
+		if len(fullNames[simpleName]) > 1 {
+			issues = append(issues, errorIssue(IssueCodeSimpleNameCollision, fmt.Sprintf("dogus[%d].name", collidingIndex[simpleName]),
+				fmt.Errorf("dogus %s must not be present at the same time because they share the simple name %q",
+					strings.Join(fullNames[simpleName], ", "), simpleName)))
+		}
+	}
+	return issues
+}
+
+// itemIssues checks the fields common to dogus and packages. The format of a non-empty version is checked with the
+// given validateVersionFormat function. The paths of the issues are relative to the item.
+func itemIssues(name string, version string, state TargetState, validateVersionFormat func(string) error) []ValidationIssue {
+	var issues []ValidationIssue
+
+	if name == "" {
+		issues = append(issues, errorIssue(IssueCodeInvalidName, "name", errors.New("name must not be empty")))
+	}
+	if state == TargetStatePresent && version == "" {
+		issues = append(issues, errorIssue(IssueCodeMissingVersion, "version",
+			fmt.Errorf("version of %q must not be empty if the target state is %s", name, state)))
+	}
+	if state == TargetStateIgnore {
+		issues = append(issues, errorIssue(IssueCodeInternalTargetState, "targetState",
+			fmt.Errorf("target state of %q must not be %s because it is only used internally", name, state)))
+	}
+	if version == "" {
+		return issues
+	}
+
+	err := validateVersionFormat(version)
+	if err != nil {
+		issues = append(issues, errorIssue(IssueCodeInvalidVersion, "version", fmt.Errorf("version %q of %q is invalid: %w", version, name, err)))
+	}
+	if state == TargetStateAbsent {
+		issues = append(issues, warningIssue(IssueCodeIgnoredVersion, "version",
+			fmt.Sprintf("version %q of %q is ignored because the target state is %s", version, name, state)))
+	}
+	return issues
+}
+
+// validateVersion checks that the given version is an exact version in the format used by the cesapp, f. i.
+// "1.2.3-4", see parseVersion.
+func validateVersion(version string) error {
+	_, err := parseVersion(version)
+	return err
+}
+
+// parseVersion parses the given exact version in the format used by the cesapp. The version must consist of exactly
+// three numeric parts and an optional numeric extension separated by a hyphen, f. i. "1.2.3" or "1.2.3-4". Versions
+// with a nano part like "1.2.3.4-5" are rejected although core.ParseVersion accepts them.
+func parseVersion(version string) (core.Version, error) {
+	if version == "" {
+		return core.Version{}, errors.New("version must not be empty")
+	}
+	if strings.TrimSpace(version) != version {
+		return core.Version{}, errors.New("version must not contain surrounding whitespace")
+	}
+	if strings.ContainsAny(version, "=<>") {
+		return core.Version{}, errors.New("version must not contain a comparison operator")
+	}
+
+	parsed, err := core.ParseVersion(version)
+	if err != nil {
+		return core.Version{}, err
+	}
+	numericParts, _, _ := strings.Cut(version, "-")
+	if strings.Count(numericParts, ".") != 2 {
+		return core.Version{}, fmt.Errorf("version must consist of exactly three numeric parts and an optional extension like %q", "1.2.3-4")
+	}
+
+	return parsed, nil
+}
+
+// validateVersionConstraint checks that the given version is either an exact version or a version prefixed with one of
+// the comparison operators supported by the cesapp, f. i. ">=1.2.3-4".
+func validateVersionConstraint(version string) error {
+	for _, operator := range versionConstraintOperators {
+		if strings.HasPrefix(version, operator) {
+			return validateVersion(strings.TrimPrefix(version, operator))
+		}
+	}
+	return validateVersion(version)
+}
+
+// validateRegistryKeyPath checks that the given registry key path, f. i. "_global/fqdn", can not address unintended
+// keys. An empty path or a path with empty segments could otherwise lead to the deletion of a whole section.
+func validateRegistryKeyPath(keyPath string) error {
+	if keyPath == "" {
+		return errors.New("registry key path must not be empty")
+	}
+	if strings.HasPrefix(keyPath, registryKeySeparator) || strings.HasSuffix(keyPath, registryKeySeparator) {
+		return fmt.Errorf("registry key path %q must not start or end with %q", keyPath, registryKeySeparator)
+	}
+	if strings.Contains(keyPath, registryKeySeparator+registryKeySeparator) {
+		return fmt.Errorf("registry key path %q must not contain empty segments", keyPath)
+	}
+
+	return nil
+}
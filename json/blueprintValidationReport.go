@@ -0,0 +1,150 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidationSeverity classifies a ValidationIssue.
+type ValidationSeverity string
+
+const (
+	// SeverityError marks an issue which makes the blueprint invalid, see BlueprintV1.Validate.
+	SeverityError ValidationSeverity = "error"
+	// SeverityWarning marks a non-fatal issue. The blueprint can be applied, but probably not as intended.
+	SeverityWarning ValidationSeverity = "warning"
+)
+
+// Codes of the issues found by BlueprintV1.ValidationReport.
+const (
+	IssueCodeInvalidAPIVersion      = "invalidApiVersion"
+	IssueCodeMissingID              = "missingId"
+	IssueCodeMissingCesAppVersion   = "missingCesappVersion"
+	IssueCodeInvalidName            = "invalidName"
+	IssueCodeMissingVersion         = "missingVersion"
+	IssueCodeInvalidVersion         = "invalidVersion"
+	IssueCodeInvalidKeyPath         = "invalidKeyPath"
+	IssueCodeDuplicate              = "duplicate"
+	IssueCodeConflictingTargetState = "conflictingTargetState"
+	IssueCodeSimpleNameCollision    = "simpleNameCollision"
+	IssueCodeInternalTargetState    = "internalTargetState"
+	// IssueCodeIgnoredVersion is a warning for a version of an absent dogu or package, which is not interpreted.
+	IssueCodeIgnoredVersion = "ignoredVersion"
+	// IssueCodeNonStringValue is a warning for a registry config value which is not a string and therefore may not be
+	// storable in the registry, see RegistryConfig.ValidateValueTypes.
+	IssueCodeNonStringValue = "nonStringValue"
+)
+
+// ValidationIssue describes a single problem of a blueprint found by BlueprintV1.ValidationReport.
+type ValidationIssue struct {
+	// Severity tells whether the issue makes the blueprint invalid.
+	Severity ValidationSeverity `json:"severity"`
+	// Code identifies the kind of issue, see the IssueCode constants.
+	Code string `json:"code"`
+	// Path points to the offending JSON field, f. i. "dogus[2].version" or "registryConfig._global.fqdn".
+	Path string `json:"path"`
+	// Message describes the issue for humans.
+	Message string `json:"message"`
+	// err contains the error returned by Validate for issues with SeverityError.
+	err error
+}
+
+// ValidationReport checks the blueprint and returns every problem as a structured ValidationIssue, so that f. i.
+// editors can highlight the offending fields. Besides errors, the report contains warnings for non-fatal concerns. The
+// blueprint is valid if the report contains no issue with SeverityError, see Validate. The issues are grouped by the
+// top-level field of the blueprint they belong to.
+func (b BlueprintV1) ValidationReport() []ValidationIssue {
+	var issues []ValidationIssue
+
+	switch {
+	case b.API == "":
+		issues = append(issues, errorIssue(IssueCodeInvalidAPIVersion, "blueprintApi", ErrMissingAPIVersion))
+	case b.API != V1:
+		issues = append(issues, errorIssue(IssueCodeInvalidAPIVersion, "blueprintApi",
+			fmt.Errorf("%w %q, expected %q", ErrUnsupportedAPIVersion, b.API, V1)))
+	}
+	if b.ID == "" {
+		issues = append(issues, errorIssue(IssueCodeMissingID, "blueprintId", errors.New("blueprint ID must not be empty")))
+	}
+	if b.CesAppVersion == "" {
+		issues = append(issues, errorIssue(IssueCodeMissingCesAppVersion, "cesappVersion", errors.New("cesapp version must not be empty")))
+	}
+
+	for i, dogu := range b.Dogus {
+		issues = append(issues, withContext(dogu.validationIssues(), fmt.Sprintf("dogus[%d]", i), fmt.Sprintf("dogu at index %d is invalid", i))...)
+	}
+	issues = append(issues, b.doguDuplicateAndConflictIssues()...)
+	issues = append(issues, b.simpleNameCollisionIssues()...)
+
+	for i, pkg := range b.Packages {
+		issues = append(issues, withContext(pkg.validationIssues(), fmt.Sprintf("packages[%d]", i), fmt.Sprintf("package at index %d is invalid", i))...)
+	}
+	issues = append(issues, b.packageDuplicateAndConflictIssues()...)
+
+	issues = append(issues, valueTypeIssues("registryConfig", b.RegistryConfig)...)
+	for i, keyPath := range b.RegistryConfigAbsent {
+		err := validateRegistryKeyPath(keyPath)
+		if err != nil {
+			issues = append(issues, withContext([]ValidationIssue{errorIssue(IssueCodeInvalidKeyPath, "", err)},
+				fmt.Sprintf("registryConfigAbsent[%d]", i), fmt.Sprintf("absent registry config entry at index %d is invalid", i))...)
+		}
+	}
+	issues = append(issues, valueTypeIssues("registryConfigEncrypted", RegistryConfig(b.RegistryConfigEncrypted))...)
+
+	return issues
+}
+
+func errorIssue(code, path string, err error) ValidationIssue {
+	return ValidationIssue{Severity: SeverityError, Code: code, Path: path, Message: err.Error(), err: err}
+}
+
+func warningIssue(code, path, message string) ValidationIssue {
+	return ValidationIssue{Severity: SeverityWarning, Code: code, Path: path, Message: message}
+}
+
+// withContext prefixes the paths of the given issues with the path of their parent field and wraps their errors with
+// the given description of the parent field. The messages are kept as they are.
+func withContext(issues []ValidationIssue, path string, context string) []ValidationIssue {
+	for i := range issues {
+		if issues[i].Path == "" {
+			issues[i].Path = path
+		} else {
+			issues[i].Path = path + "." + issues[i].Path
+		}
+		if issues[i].err != nil {
+			issues[i].err = fmt.Errorf("%s: %w", context, issues[i].err)
+		}
+	}
+	return issues
+}
+
+// validationError joins the errors of all issues with SeverityError. It returns nil if there are none.
+func validationError(issues []ValidationIssue) error {
+	var errs []error
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue.err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// valueTypeIssues warns about every registry config value which is not a string.
+func valueTypeIssues(field string, config RegistryConfig) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, section := range unionOfKeys(config, nil) {
+		for _, key := range unionOfKeys(config[section], nil) {
+			valueType, ok := registryValueTypeOf(config[section][key])
+			if ok && valueType == RegistryValueString {
+				continue
+			}
+
+			message := fmt.Sprintf("value has type %s but the registry stores strings", valueType)
+			if !ok {
+				message = fmt.Sprintf("value has unsupported type %T", config[section][key])
+			}
+			issues = append(issues, warningIssue(IssueCodeNonStringValue, fmt.Sprintf("%s.%s.%s", field, section, key), message))
+		}
+	}
+	return issues
+}
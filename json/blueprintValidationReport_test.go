@@ -0,0 +1,107 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withoutErrors removes the unexported errors from the issues so that they can be compared to literals.
+func withoutErrors(issues []ValidationIssue) []ValidationIssue {
+	result := make([]ValidationIssue, 0, len(issues))
+	for _, issue := range issues {
+		issue.err = nil
+		result = append(result, issue)
+	}
+	return result
+}
+
+func TestBlueprintV1_ValidationReport(t *testing.T) {
+	t.Run("should return no issues for valid blueprint", func(t *testing.T) {
+		sut := createValidBlueprint()
+
+		assert.Empty(t, sut.ValidationReport())
+	})
+	t.Run("should report errors with path and code", func(t *testing.T) {
+		sut := BlueprintV1{
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4"},
+				{Name: "nginx", Version: "1.2.3"},
+				{Name: "official/nginx", TargetState: TargetStateAbsent},
+				{Name: "premium/nginx", Version: "1.2.3-4"},
+			},
+			Packages:             []TargetPackage{{Version: ">=x"}},
+			RegistryConfigAbsent: []string{"redmine/theme", "/broken"},
+		}
+
+		actual := sut.ValidationReport()
+
+		expected := []ValidationIssue{
+			{Severity: SeverityError, Code: IssueCodeInvalidAPIVersion, Path: "blueprintApi", Message: "missing blueprint API version"},
+			{Severity: SeverityError, Code: IssueCodeMissingID, Path: "blueprintId", Message: "blueprint ID must not be empty"},
+			{Severity: SeverityError, Code: IssueCodeMissingCesAppVersion, Path: "cesappVersion", Message: "cesapp version must not be empty"},
+			{Severity: SeverityError, Code: IssueCodeInvalidName, Path: "dogus[1].name", Message: `dogu name "nginx" must consist of a namespace and a name separated by exactly one "/"`},
+			{Severity: SeverityError, Code: IssueCodeDuplicate, Path: "dogus[2].name", Message: `dogu "official/nginx" must not be contained more than once (index 0, 2)`},
+			{Severity: SeverityError, Code: IssueCodeConflictingTargetState, Path: "dogus[2].targetState", Message: `dogu "official/nginx" must not be both present (index 0) and absent (index 2)`},
+			{Severity: SeverityError, Code: IssueCodeSimpleNameCollision, Path: "dogus[3].name", Message: `dogus official/nginx, premium/nginx must not be present at the same time because they share the simple name "nginx"`},
+			{Severity: SeverityError, Code: IssueCodeInvalidName, Path: "packages[0].name", Message: "name must not be empty"},
+			{Severity: SeverityError, Code: IssueCodeInvalidVersion, Path: "packages[0].version", Message: `version ">=x" of "" is invalid: failed to parse major version x: strconv.Atoi: parsing "x": invalid syntax`},
+			{Severity: SeverityError, Code: IssueCodeInvalidKeyPath, Path: "registryConfigAbsent[1]", Message: `registry key path "/broken" must not start or end with "/"`},
+		}
+		assert.Equal(t, expected, withoutErrors(actual))
+
+		err := sut.Validate()
+		assert.ErrorContains(t, err, `dogu at index 1 is invalid: dogu name "nginx" must consist of a namespace`)
+		assert.ErrorContains(t, err, `absent registry config entry at index 1 is invalid: registry key path "/broken"`)
+	})
+	t.Run("should report warnings", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "official/scm", Version: "2.0.0-1", TargetState: TargetStateAbsent})
+		sut.RegistryConfig = RegistryConfig{"_global": {"fqdn": "ces.example.com", "pretty": true}}
+		sut.RegistryConfigEncrypted = EncryptedRegistryConfig{"redmine": {"secret": 42.0}}
+
+		actual := sut.ValidationReport()
+
+		expected := []ValidationIssue{
+			{Severity: SeverityWarning, Code: IssueCodeIgnoredVersion, Path: "dogus[2].version", Message: `version "2.0.0-1" of "official/scm" is ignored because the target state is absent`},
+			{Severity: SeverityWarning, Code: IssueCodeNonStringValue, Path: "registryConfig._global.pretty", Message: "value has type boolean but the registry stores strings"},
+			{Severity: SeverityWarning, Code: IssueCodeNonStringValue, Path: "registryConfigEncrypted.redmine.secret", Message: "value has type number but the registry stores strings"},
+		}
+		assert.Equal(t, expected, actual)
+		assert.NoError(t, sut.Validate())
+	})
+	t.Run("should wrap sentinel errors", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.API = "v99"
+
+		actual := sut.ValidationReport()
+
+		require.Len(t, actual, 1)
+		assert.ErrorIs(t, actual[0].err, ErrUnsupportedAPIVersion)
+		assert.ErrorIs(t, sut.Validate(), ErrUnsupportedAPIVersion)
+	})
+	t.Run("should report errors exactly if Validate fails", func(t *testing.T) {
+		blueprints := map[string]func(b *BlueprintV1){
+			"valid":            func(b *BlueprintV1) {},
+			"duplicate dogu":   func(b *BlueprintV1) { b.Dogus = append(b.Dogus, b.Dogus[0]) },
+			"missing version":  func(b *BlueprintV1) { b.Packages[0].Version = "" },
+			"unsupported API":  func(b *BlueprintV1) { b.API = "v99" },
+			"invalid key path": func(b *BlueprintV1) { b.RegistryConfigAbsent = []string{""} },
+			"conflict":         func(b *BlueprintV1) { b.Packages[1].Name = "cesapp" },
+		}
+		for name, modify := range blueprints {
+			t.Run(name, func(t *testing.T) {
+				sut := createValidBlueprint()
+				modify(&sut)
+
+				hasErrors := false
+				for _, issue := range sut.ValidationReport() {
+					hasErrors = hasErrors || issue.Severity == SeverityError
+				}
+
+				assert.Equal(t, sut.Validate() != nil, hasErrors)
+			})
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createValidBlueprint() BlueprintV1 {
+	return BlueprintV1{
+		GeneralBlueprint: GeneralBlueprint{API: V1},
+		ID:               "my-blueprint",
+		CesAppVersion:    "7.0.0-1",
+		Dogus: []TargetDogu{
+			{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStatePresent},
+			{Name: "official/redmine", TargetState: TargetStateAbsent},
+		},
+		Packages: []TargetPackage{
+			{Name: "cesapp", Version: "7.0.0-1", TargetState: TargetStatePresent},
+			{Name: "ces-commons", TargetState: TargetStateAbsent},
+		},
+	}
+}
+
+func TestBlueprintV1_Validate(t *testing.T) {
+	t.Run("should succeed for valid blueprint", func(t *testing.T) {
+		sut := createValidBlueprint()
+
+		err := sut.Validate()
+
+		require.NoError(t, err)
+	})
+	t.Run("should fail for empty header fields", func(t *testing.T) {
+		sut := BlueprintV1{}
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrMissingAPIVersion)
+		assert.ErrorContains(t, err, "blueprint ID must not be empty")
+		assert.ErrorContains(t, err, "cesapp version must not be empty")
+	})
+	t.Run("should fail for unsupported API version", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.API = "v99"
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+		assert.ErrorContains(t, err, `unsupported blueprint API version "v99", expected "v1"`)
+	})
+	t.Run("should fail for other supported API version", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.API = TestEmpty
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+		assert.ErrorContains(t, err, `unsupported blueprint API version "test/empty", expected "v1"`)
+	})
+	t.Run("should report all invalid dogus and packages", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus,
+			TargetDogu{Version: "1.0.0-1"},
+			TargetDogu{Name: "official/ldap", TargetState: TargetStatePresent},
+		)
+		sut.Packages = append(sut.Packages, TargetPackage{Name: "", TargetState: TargetStateAbsent})
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "dogu at index 2 is invalid: name must not be empty")
+		assert.ErrorContains(t, err, `dogu at index 3 is invalid: version of "official/ldap" must not be empty if the target state is present`)
+		assert.ErrorContains(t, err, "package at index 2 is invalid: name must not be empty")
+	})
+	t.Run("should report every invalid absent registry config entry", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.RegistryConfigAbsent = []string{"_global/fqdn", "", "/_global/fqdn", "redmine/", "redmine//theme"}
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "absent registry config entry at index 1 is invalid: registry key path must not be empty")
+		assert.ErrorContains(t, err, `absent registry config entry at index 2 is invalid: registry key path "/_global/fqdn" must not start or end with "/"`)
+		assert.ErrorContains(t, err, `absent registry config entry at index 3 is invalid: registry key path "redmine/" must not start or end with "/"`)
+		assert.ErrorContains(t, err, `absent registry config entry at index 4 is invalid: registry key path "redmine//theme" must not contain empty segments`)
+		assert.NotContains(t, err.Error(), "index 0")
+	})
+	t.Run("should fail for dogu without namespace", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "nginx", Version: "1.2.3-4"})
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `dogu at index 2 is invalid: dogu name "nginx" must consist of a namespace and a name`)
+	})
+}
+
+func TestBlueprintV1_Validate_duplicates(t *testing.T) {
+	t.Run("should succeed without duplicates", func(t *testing.T) {
+		sut := createValidBlueprint()
+
+		err := sut.Validate()
+
+		require.NoError(t, err)
+	})
+	t.Run("should list every duplicate dogu and package", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus,
+			TargetDogu{Name: "official/nginx", Version: "1.3.0-1"},
+			TargetDogu{Name: "official/redmine", Version: "5.0.0-1"},
+			TargetDogu{Name: "official/nginx", TargetState: TargetStateAbsent},
+		)
+		sut.Packages = append(sut.Packages, TargetPackage{Name: "cesapp", Version: "7.1.0-1"})
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `dogu "official/nginx" must not be contained more than once (index 0, 2, 4)`)
+		assert.ErrorContains(t, err, `dogu "official/redmine" must not be contained more than once (index 1, 3)`)
+		assert.ErrorContains(t, err, `package "cesapp" must not be contained more than once (index 0, 2)`)
+	})
+}
+
+func TestBlueprintV1_Validate_conflictingTargetStates(t *testing.T) {
+	t.Run("should succeed without conflicts", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "official/nginx", Version: "1.3.0-1"})
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "both present")
+	})
+	t.Run("should name all conflicting entries", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus,
+			TargetDogu{Name: "official/nginx", TargetState: TargetStateAbsent},
+			TargetDogu{Name: "official/redmine", Version: "5.0.0-1"},
+			TargetDogu{Name: "official/nginx", TargetState: TargetStateAbsent},
+		)
+		sut.Packages = append(sut.Packages, TargetPackage{Name: "cesapp", TargetState: TargetStateAbsent})
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `dogu "official/nginx" must not be both present (index 0) and absent (index 2, 4)`)
+		assert.ErrorContains(t, err, `dogu "official/redmine" must not be both present (index 3) and absent (index 1)`)
+		assert.ErrorContains(t, err, `package "cesapp" must not be both present (index 0) and absent (index 2)`)
+	})
+}
+
+func TestBlueprintV1_ValidateRegistryConfigValueTypes(t *testing.T) {
+	t.Run("should check registry config and encrypted registry config", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.RegistryConfig = RegistryConfig{"_global": {"fqdn": "ces.example.com", "pretty": true}}
+		sut.RegistryConfigEncrypted = EncryptedRegistryConfig{"redmine": {"secret": 42}}
+
+		err := sut.ValidateRegistryConfigValueTypes()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `invalid registry config: registry config value at "_global/pretty" has type boolean`)
+		assert.ErrorContains(t, err, `invalid encrypted registry config: registry config value at "redmine/secret" has type number`)
+	})
+	t.Run("should succeed for allowed types", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.RegistryConfig = RegistryConfig{"_global": {"fqdn": "ces.example.com", "pretty": true}}
+
+		assert.NoError(t, sut.ValidateRegistryConfigValueTypes(RegistryValueString, RegistryValueBoolean))
+	})
+}
+
+func TestBlueprintV1_Validate_ignoredTargetState(t *testing.T) {
+	rawBlueprint := []byte(`{
+		"blueprintApi": "v1",
+		"blueprintId": "my-blueprint",
+		"cesappVersion": "7.0.0-1",
+		"dogus": [{"name": "official/nginx", "targetState": "ignore"}],
+		"packages": [{"name": "cesapp", "targetState": "ignore"}]
+	}`)
+
+	parsed, err := ParseBlueprintTyped(rawBlueprint)
+	require.NoError(t, err)
+	sut := parsed.(*BlueprintV1)
+
+	err = sut.Validate()
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `dogu at index 0 is invalid: target state of "official/nginx" must not be ignore because it is only used internally`)
+	assert.ErrorContains(t, err, `package at index 0 is invalid: target state of "cesapp" must not be ignore`)
+	assert.Contains(t, withoutErrors(sut.ValidationReport()), ValidationIssue{Severity: SeverityError, Code: IssueCodeInternalTargetState,
+		Path: "dogus[0].targetState", Message: `target state of "official/nginx" must not be ignore because it is only used internally`})
+}
+
+func TestBlueprintV1_Validate_simpleNameCollisions(t *testing.T) {
+	t.Run("should fail for present dogus with the same simple name", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus,
+			TargetDogu{Name: "premium/nginx", Version: "1.2.3-4"},
+			TargetDogu{Name: "official/scm", Version: "2.0.0-1"},
+			TargetDogu{Name: "testing/nginx", Version: "1.2.3-4"},
+		)
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `dogus official/nginx, premium/nginx, testing/nginx must not be present at the same time because they share the simple name "nginx"`)
+		assert.NotContains(t, err.Error(), `"scm"`)
+	})
+	t.Run("should succeed if only one of them is present", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "premium/redmine", Version: "5.0.0-1"})
+
+		err := sut.Validate()
+
+		require.NoError(t, err)
+	})
+	t.Run("should not report duplicates of the same dogu as collision", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Dogus = append(sut.Dogus, TargetDogu{Name: "official/nginx", Version: "1.2.3-4"})
+
+		err := sut.Validate()
+
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "simple name")
+	})
+}
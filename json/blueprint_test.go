@@ -2,11 +2,13 @@ package json
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestTargetState_String(t *testing.T) {
@@ -25,6 +27,11 @@ func TestTargetState_String(t *testing.T) {
 			TargetStateAbsent,
 			"absent",
 		},
+		{
+			"String() map enum to string",
+			TargetStateIgnore,
+			"ignore",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -35,6 +42,33 @@ func TestTargetState_String(t *testing.T) {
 	}
 }
 
+func TestTargetStates(t *testing.T) {
+	assert.Equal(t, []TargetState{TargetStatePresent, TargetStateAbsent, TargetStateIgnore}, TargetStates())
+	assert.Equal(t, []string{"present", "absent", "ignore"}, TargetStateStrings())
+}
+
+func TestParseTargetState(t *testing.T) {
+	t.Run("should parse all target state strings", func(t *testing.T) {
+		for _, state := range TargetStates() {
+			actual, err := ParseTargetState(state.String())
+
+			require.NoError(t, err)
+			assert.Equal(t, state, actual)
+		}
+	})
+	t.Run("should ignore case and whitespace", func(t *testing.T) {
+		actual, err := ParseTargetState(" Absent ")
+
+		require.NoError(t, err)
+		assert.Equal(t, TargetStateAbsent, actual)
+	})
+	t.Run("should fail for unknown target state", func(t *testing.T) {
+		_, err := ParseTargetState("gone")
+
+		assert.ErrorContains(t, err, `unknown target state "gone", valid target states are present, absent, ignore`)
+	})
+}
+
 func TestTargetState_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -54,6 +88,18 @@ func TestTargetState_MarshalJSON(t *testing.T) {
 			[]byte(`"absent"`),
 			false,
 		},
+		{
+			"MarshalJSON to bytes",
+			TargetStateIgnore,
+			[]byte(`"ignore"`),
+			false,
+		},
+		{
+			"MarshalJSON fails for undefined value",
+			TargetState(99),
+			nil,
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -88,13 +134,78 @@ func TestTargetState_UnmarshalJSON_firstValue(t *testing.T) {
 	assert.EqualValues(t, TargetStatePresent, sut)
 }
 
-func TestTargetState_UnmarshalJSON_unknownValueParsesToFirstState(t *testing.T) {
-	jsonBlob := []byte(`"test"`)
+func TestTargetState_UnmarshalJSON_unknownValueReturnsError(t *testing.T) {
+	jsonBlob := []byte(`"presnt"`)
+	var sut TargetState
+	err := json.Unmarshal(jsonBlob, &sut)
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `unknown target state "presnt"`)
+	assert.ErrorContains(t, err, "valid target states are present, absent, ignore")
+}
+
+func TestTargetState_UnmarshalJSON_ignoresCaseAndWhitespace(t *testing.T) {
+	tests := []struct {
+		jsonBlob string
+		want     TargetState
+	}{
+		{`"Present"`, TargetStatePresent},
+		{`"ABSENT"`, TargetStateAbsent},
+		{`" present "`, TargetStatePresent},
+		{`"\tAbsent\n"`, TargetStateAbsent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jsonBlob, func(t *testing.T) {
+			sut := TargetStateIgnore
+			err := json.Unmarshal([]byte(tt.jsonBlob), &sut)
+
+			require.NoError(t, err)
+			assert.EqualValues(t, tt.want, sut)
+		})
+	}
+}
+
+func TestTargetState_UnmarshalJSON_unknownValueWithWhitespaceReturnsError(t *testing.T) {
 	var sut TargetState
+	err := json.Unmarshal([]byte(`" presnt "`), &sut)
+
+	assert.ErrorContains(t, err, `unknown target state " presnt "`)
+}
+
+func TestTargetState_UnmarshalJSON_nullKeepsDefault(t *testing.T) {
+	jsonBlob := []byte(`null`)
+	sut := TargetStateAbsent
 	err := json.Unmarshal(jsonBlob, &sut)
 
 	require.NoError(t, err)
-	assert.EqualValues(t, TargetStatePresent, sut)
+	assert.EqualValues(t, TargetStateAbsent, sut)
+}
+
+func TestTargetDogu_UnmarshalJSON_omittedTargetStateDefaultsToPresent(t *testing.T) {
+	jsonBlob := []byte(`{"name":"official/nginx","version":"1.2.3-4"}`)
+	var sut TargetDogu
+	err := json.Unmarshal(jsonBlob, &sut)
+
+	require.NoError(t, err)
+	assert.EqualValues(t, TargetStatePresent, sut.TargetState)
+}
+
+func TestTargetDogu_UnmarshalJSON_invalidTargetStateReturnsError(t *testing.T) {
+	jsonBlob := []byte(`{"name":"official/nginx","version":"1.2.3-4","targetState":"removed"}`)
+	var sut TargetDogu
+	err := json.Unmarshal(jsonBlob, &sut)
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `unknown target state "removed"`)
+}
+
+func TestTargetState_MarshalJSON_undefinedValueInStruct(t *testing.T) {
+	sut := TargetDogu{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetState(99)}
+
+	_, err := json.Marshal(sut)
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "cannot marshal TargetState 99")
 }
 
 func TestTargetState_UnmarshalJSON_error(t *testing.T) {
@@ -104,3 +215,485 @@ func TestTargetState_UnmarshalJSON_error(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestBlueprintV1_FindDogu(t *testing.T) {
+	sut := BlueprintV1{Dogus: []TargetDogu{
+		{Name: "official/nginx", Version: "1.2.3-4"},
+		{Name: "official/redmine", Version: "5.0.0-1", TargetState: TargetStateAbsent},
+	}}
+
+	t.Run("should find dogu by full name", func(t *testing.T) {
+		actual, found := sut.FindDogu("official/redmine")
+
+		assert.True(t, found)
+		assert.Equal(t, TargetDogu{Name: "official/redmine", Version: "5.0.0-1", TargetState: TargetStateAbsent}, actual)
+	})
+	t.Run("should not match simple name", func(t *testing.T) {
+		actual, found := sut.FindDogu("redmine")
+
+		assert.False(t, found)
+		assert.Equal(t, TargetDogu{}, actual)
+	})
+}
+
+func TestBlueprintV1_FindPackage(t *testing.T) {
+	sut := BlueprintV1{Packages: []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}}}
+
+	t.Run("should find package by name", func(t *testing.T) {
+		actual, found := sut.FindPackage("cesapp")
+
+		assert.True(t, found)
+		assert.Equal(t, TargetPackage{Name: "cesapp", Version: "7.0.0-1"}, actual)
+	})
+	t.Run("should report missing package", func(t *testing.T) {
+		actual, found := sut.FindPackage("ces-commons")
+
+		assert.False(t, found)
+		assert.Equal(t, TargetPackage{}, actual)
+	})
+	t.Run("should return first package regardless of package manager", func(t *testing.T) {
+		sut := BlueprintV1{Packages: []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+			{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+		}}
+
+		actual, found := sut.FindPackage("curl")
+
+		assert.True(t, found)
+		assert.Equal(t, TargetPackage{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"}, actual)
+	})
+}
+
+func TestTargetState_roundTrip(t *testing.T) {
+	for _, state := range TargetStates() {
+		t.Run(state.String(), func(t *testing.T) {
+			rawJson, err := json.Marshal(TargetDogu{Name: "official/nginx", TargetState: state})
+			require.NoError(t, err)
+			var fromJson TargetDogu
+			require.NoError(t, json.Unmarshal(rawJson, &fromJson))
+			assert.Equal(t, state, fromJson.TargetState)
+
+			rawYaml, err := yaml.Marshal(TargetPackage{Name: "cesapp", TargetState: state})
+			require.NoError(t, err)
+			var fromYaml TargetPackage
+			require.NoError(t, yaml.Unmarshal(rawYaml, &fromYaml))
+			assert.Equal(t, state, fromYaml.TargetState)
+		})
+	}
+
+	t.Run("should marshal ignore", func(t *testing.T) {
+		actual, err := json.Marshal(TargetDogu{Name: "official/nginx", TargetState: TargetStateIgnore})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "official/nginx", "version": "", "targetState": "ignore"}`, string(actual))
+	})
+}
+
+func TestTargetState_MarshalYAML(t *testing.T) {
+	for _, state := range []TargetState{TargetStatePresent, TargetStateAbsent, TargetStateIgnore} {
+		actual, err := yaml.Marshal(state)
+
+		require.NoError(t, err)
+		assert.Equal(t, state.String()+"\n", string(actual))
+	}
+
+	_, err := yaml.Marshal(TargetState(99))
+	assert.ErrorContains(t, err, "cannot marshal TargetState 99")
+}
+
+func TestTargetState_UnmarshalYAML(t *testing.T) {
+	t.Run("should unmarshal known value", func(t *testing.T) {
+		var sut TargetState
+		err := yaml.Unmarshal([]byte("absent"), &sut)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, TargetStateAbsent, sut)
+	})
+	t.Run("should ignore case and whitespace", func(t *testing.T) {
+		var sut TargetState
+		err := yaml.Unmarshal([]byte("' Absent '"), &sut)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, TargetStateAbsent, sut)
+	})
+	t.Run("should keep default for omitted state", func(t *testing.T) {
+		var sut TargetDogu
+		err := yaml.Unmarshal([]byte("name: official/nginx\nversion: 1.2.3-4\n"), &sut)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, TargetStatePresent, sut.TargetState)
+	})
+	t.Run("should fail for unknown value", func(t *testing.T) {
+		var sut TargetState
+		err := yaml.Unmarshal([]byte("presnt"), &sut)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `unknown target state "presnt"`)
+	})
+	t.Run("should fail for non-scalar value", func(t *testing.T) {
+		var sut TargetState
+		err := yaml.Unmarshal([]byte("[present]"), &sut)
+
+		assert.ErrorContains(t, err, "cannot unmarshal value")
+	})
+}
+
+func TestBlueprintV1_yamlRoundTrip(t *testing.T) {
+	rawBlueprint := []byte(`{
+		"blueprintApi": "v1",
+		"blueprintId": "my-blueprint",
+		"cesappVersion": "7.0.0-1",
+		"dogus": [
+			{"name": "official/nginx", "version": "1.2.3-4", "targetState": "present"},
+			{"name": "official/redmine", "version": "", "targetState": "absent"}
+		],
+		"packages": [{"name": "cesapp", "version": "7.0.0-1", "targetState": "present"}],
+		"registryConfig": {"_global": {"fqdn": "ces.example.com", "pretty": true, "port": 443, "ratio": 0.5}, "redmine": {"limits": {"users": 10, "sizes": [1, 2.5]}}},
+		"registryConfigAbsent": ["redmine/theme"],
+		"registryConfigEncrypted": {"redmine": {"secret": "s3cr3t", "pin": 1234}}
+	}`)
+	var fromJson BlueprintV1
+	require.NoError(t, json.Unmarshal(rawBlueprint, &fromJson))
+
+	rawYaml, err := yaml.Marshal(fromJson)
+	require.NoError(t, err)
+	assert.Contains(t, string(rawYaml), "blueprintApi: v1\n")
+	assert.Contains(t, string(rawYaml), "targetState: absent\n")
+
+	var fromYaml BlueprintV1
+	require.NoError(t, yaml.Unmarshal(rawYaml, &fromYaml))
+	assert.Equal(t, fromJson, fromYaml)
+	assert.True(t, fromJson.Equal(fromYaml))
+	assert.True(t, Diff(fromJson, fromYaml).IsEmpty())
+}
+
+func TestBlueprintV1_metadataAndComments(t *testing.T) {
+	rawBlueprint := []byte(`{
+		"blueprintApi": "v1",
+		"blueprintId": "my-blueprint",
+		"metadata": {"author": "jane", "ticket": "CES-123"},
+		"dogus": [{"name": "official/nginx", "version": "1.2.3-4", "comment": "pinned because of CES-42"}],
+		"packages": [{"name": "cesapp", "version": "7.0.0-1", "comment": "latest"}]
+	}`)
+
+	t.Run("should preserve metadata and comments in json", func(t *testing.T) {
+		actual, err := ParseBlueprintStrict(rawBlueprint)
+		require.NoError(t, err)
+
+		sut := actual.(*BlueprintV1)
+		assert.Equal(t, map[string]string{"author": "jane", "ticket": "CES-123"}, sut.Metadata)
+		assert.Equal(t, "pinned because of CES-42", sut.Dogus[0].Comment)
+		assert.Equal(t, "latest", sut.Packages[0].Comment)
+
+		marshalled, err := json.Marshal(sut)
+		require.NoError(t, err)
+		var reparsed BlueprintV1
+		require.NoError(t, json.Unmarshal(marshalled, &reparsed))
+		assert.Equal(t, *sut, reparsed)
+	})
+	t.Run("should preserve metadata and comments in yaml", func(t *testing.T) {
+		var fromJson BlueprintV1
+		require.NoError(t, json.Unmarshal(rawBlueprint, &fromJson))
+
+		rawYaml, err := yaml.Marshal(fromJson)
+		require.NoError(t, err)
+		var fromYaml BlueprintV1
+		require.NoError(t, yaml.Unmarshal(rawYaml, &fromYaml))
+
+		assert.Equal(t, fromJson, fromYaml)
+	})
+	t.Run("should omit empty metadata and comments", func(t *testing.T) {
+		sut := BlueprintV1{GeneralBlueprint: GeneralBlueprint{API: V1}, Dogus: []TargetDogu{{Name: "official/nginx"}}}
+
+		actual, err := json.Marshal(sut)
+
+		require.NoError(t, err)
+		assert.NotContains(t, string(actual), "metadata")
+		assert.NotContains(t, string(actual), "comment")
+	})
+	t.Run("should ignore metadata and comments when comparing", func(t *testing.T) {
+		sut := BlueprintV1{Dogus: []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}}}
+		other := BlueprintV1{
+			Dogus:    []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4", Comment: "pinned"}},
+			Metadata: map[string]string{"author": "jane"},
+		}
+
+		assert.True(t, sut.Equal(other))
+	})
+	t.Run("should deep copy metadata", func(t *testing.T) {
+		sut := BlueprintV1{Metadata: map[string]string{"author": "jane"}}
+
+		actual := sut.DeepCopy()
+		actual.Metadata["author"] = "john"
+
+		assert.Equal(t, "jane", sut.Metadata["author"])
+	})
+}
+
+func TestBlueprintV1_DeepCopy(t *testing.T) {
+	sut := BlueprintV1{
+		GeneralBlueprint:        GeneralBlueprint{API: V1},
+		ID:                      "my-blueprint",
+		CesAppVersion:           "7.0.0-1",
+		Dogus:                   []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}},
+		Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}},
+		RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com"}},
+		RegistryConfigAbsent:    []string{"redmine/theme"},
+		RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+	}
+	original := BlueprintV1{
+		GeneralBlueprint:        GeneralBlueprint{API: V1},
+		ID:                      "my-blueprint",
+		CesAppVersion:           "7.0.0-1",
+		Dogus:                   []TargetDogu{{Name: "official/nginx", Version: "1.2.3-4"}},
+		Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}},
+		RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com"}},
+		RegistryConfigAbsent:    []string{"redmine/theme"},
+		RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+	}
+
+	actual := sut.DeepCopy()
+	require.Equal(t, original, actual)
+
+	actual.Dogus[0].TargetState = TargetStateAbsent
+	actual.Packages[0].Version = "7.1.0-1"
+	actual.RegistryConfig["_global"]["fqdn"] = "changed.example.com"
+	actual.RegistryConfigAbsent[0] = "changed"
+	actual.RegistryConfigEncrypted["redmine"]["secret"] = "changed"
+
+	assert.Equal(t, original, sut)
+}
+
+func TestParseBlueprint(t *testing.T) {
+	t.Run("should parse API version", func(t *testing.T) {
+		actual, err := ParseBlueprint([]byte(`{"blueprintApi": "v1", "blueprintId": "my-blueprint"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, GeneralBlueprint{API: V1}, actual)
+	})
+	t.Run("should return ErrInvalidJSON for invalid syntax", func(t *testing.T) {
+		_, err := ParseBlueprint([]byte(`{"blueprintApi": `))
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidJSON)
+		assert.ErrorContains(t, err, "could not parse blueprint. Please check the blueprint for validity")
+	})
+}
+
+func TestBlueprintV1_Equal(t *testing.T) {
+	createBlueprint := func() BlueprintV1 {
+		return BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4"},
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+			},
+			Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}, {Name: "ces-commons", Version: "1.0.0-1"}},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com", "list": []interface{}{"a"}}},
+			RegistryConfigAbsent:    []string{"redmine/theme", "_global/mail"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+		}
+	}
+
+	t.Run("should be equal independent of order", func(t *testing.T) {
+		sut := createBlueprint()
+		other := createBlueprint()
+		other.Dogus[0], other.Dogus[1] = other.Dogus[1], other.Dogus[0]
+		other.Packages[0], other.Packages[1] = other.Packages[1], other.Packages[0]
+		other.RegistryConfigAbsent[0], other.RegistryConfigAbsent[1] = other.RegistryConfigAbsent[1], other.RegistryConfigAbsent[0]
+
+		assert.True(t, sut.Equal(other))
+		assert.True(t, other.Equal(sut))
+	})
+	t.Run("should be equal independent of repeated entries", func(t *testing.T) {
+		sut := createBlueprint()
+		other := createBlueprint()
+		other.Dogus = append(other.Dogus, other.Dogus[0])
+		other.Packages = append(other.Packages, other.Packages[1])
+		other.RegistryConfigAbsent = append(other.RegistryConfigAbsent, other.RegistryConfigAbsent[0])
+
+		assert.True(t, sut.Equal(other))
+		assert.True(t, other.Equal(sut))
+	})
+	t.Run("should treat empty and missing values as equal", func(t *testing.T) {
+		sut := BlueprintV1{Dogus: []TargetDogu{}, RegistryConfig: RegistryConfig{"_global": {}}}
+
+		assert.True(t, sut.Equal(BlueprintV1{}))
+	})
+	tests := []struct {
+		name   string
+		modify func(b *BlueprintV1)
+	}{
+		{"different ID", func(b *BlueprintV1) { b.ID = "other" }},
+		{"different API", func(b *BlueprintV1) { b.API = TestEmpty }},
+		{"different cesapp version", func(b *BlueprintV1) { b.CesAppVersion = "7.1.0-1" }},
+		{"different dogu", func(b *BlueprintV1) { b.Dogus[0].Version = "1.2.3-5" }},
+		{"additional dogu", func(b *BlueprintV1) { b.Dogus = append(b.Dogus, TargetDogu{Name: "official/scm"}) }},
+		{"different package", func(b *BlueprintV1) { b.Packages[1].TargetState = TargetStateAbsent }},
+		{"missing package", func(b *BlueprintV1) { b.Packages = b.Packages[:1] }},
+		{"different absent entry", func(b *BlueprintV1) { b.RegistryConfigAbsent[0] = "other" }},
+		{"missing absent entry", func(b *BlueprintV1) { b.RegistryConfigAbsent = nil }},
+		{"different registry config", func(b *BlueprintV1) { b.RegistryConfig["_global"]["list"] = []interface{}{"b"} }},
+		{"different encrypted registry config", func(b *BlueprintV1) { b.RegistryConfigEncrypted["redmine"]["secret"] = "other" }},
+	}
+	for _, tt := range tests {
+		t.Run("should not be equal for "+tt.name, func(t *testing.T) {
+			sut := createBlueprint()
+			other := createBlueprint()
+			tt.modify(&other)
+
+			assert.False(t, sut.Equal(other))
+		})
+	}
+}
+
+func TestBlueprintApi_IsSupported(t *testing.T) {
+	assert.True(t, V1.IsSupported())
+	assert.True(t, TestEmpty.IsSupported())
+	assert.False(t, BlueprintApi("v99").IsSupported())
+	assert.False(t, BlueprintApi("").IsSupported())
+}
+
+func TestBlueprintApi_Validate(t *testing.T) {
+	t.Run("should succeed for supported versions", func(t *testing.T) {
+		for _, api := range SupportedBlueprintApis() {
+			assert.NoError(t, api.Validate())
+		}
+	})
+	t.Run("should fail for empty version", func(t *testing.T) {
+		err := BlueprintApi("").Validate()
+
+		assert.ErrorIs(t, err, ErrMissingAPIVersion)
+	})
+	t.Run("should fail for unsupported version", func(t *testing.T) {
+		err := BlueprintApi("v99").Validate()
+
+		assert.ErrorIs(t, err, ErrUnsupportedAPIVersion)
+		assert.ErrorContains(t, err, `unsupported blueprint API version "v99", supported versions are test/empty, v1`)
+	})
+}
+
+func TestBlueprintV1_omittedTargetState(t *testing.T) {
+	t.Run("omitted and explicit present target state should resolve identically", func(t *testing.T) {
+		var omitted, explicit BlueprintV1
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"dogus": [{"name": "official/nginx", "version": "1.2.3-4"}],
+			"packages": [{"name": "cesapp", "version": "7.0.0-1"}]
+		}`), &omitted))
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"dogus": [{"name": "official/nginx", "version": "1.2.3-4", "targetState": "present"}],
+			"packages": [{"name": "cesapp", "version": "7.0.0-1", "targetState": "present"}]
+		}`), &explicit))
+
+		assert.Equal(t, explicit, omitted)
+		assert.Equal(t, TargetStatePresent, omitted.Dogus[0].TargetState)
+		assert.Equal(t, TargetStatePresent, omitted.Packages[0].TargetState)
+	})
+}
+
+func TestBlueprintV1_DogusByState(t *testing.T) {
+	sut := BlueprintV1{Dogus: []TargetDogu{
+		{Name: "official/nginx", Version: "1.2.3-4"},
+		{Name: "official/redmine", TargetState: TargetStateAbsent},
+		{Name: "official/scm", Version: "2.0.0-1", TargetState: TargetStatePresent},
+	}}
+
+	assert.Equal(t, []TargetDogu{
+		{Name: "official/nginx", Version: "1.2.3-4"},
+		{Name: "official/scm", Version: "2.0.0-1", TargetState: TargetStatePresent},
+	}, sut.DogusByState(TargetStatePresent))
+	assert.Equal(t, []TargetDogu{{Name: "official/redmine", TargetState: TargetStateAbsent}}, sut.DogusByState(TargetStateAbsent))
+	assert.Empty(t, sut.DogusByState(TargetStateIgnore))
+}
+
+func TestBlueprintV1_PackagesByState(t *testing.T) {
+	sut := BlueprintV1{Packages: []TargetPackage{
+		{Name: "cesapp", Version: "7.0.0-1"},
+		{Name: "ces-commons", TargetState: TargetStateAbsent},
+	}}
+
+	assert.Equal(t, []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}}, sut.PackagesByState(TargetStatePresent))
+	assert.Equal(t, []TargetPackage{{Name: "ces-commons", TargetState: TargetStateAbsent}}, sut.PackagesByState(TargetStateAbsent))
+}
+
+func TestBlueprintV1_PackagesForManager(t *testing.T) {
+	sut := BlueprintV1{Packages: []TargetPackage{
+		{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+		{Name: "cesapp", Version: "7.0.0-1"},
+		{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+	}}
+
+	assert.Equal(t, []TargetPackage{sut.Packages[0], sut.Packages[1]}, sut.PackagesForManager("apt"))
+	assert.Equal(t, []TargetPackage{sut.Packages[1], sut.Packages[2]}, sut.PackagesForManager("yum"))
+	assert.Equal(t, []TargetPackage{sut.Packages[1]}, sut.PackagesForManager("zypper"))
+	assert.Nil(t, BlueprintV1{}.PackagesForManager("apt"))
+}
+
+func TestBlueprintV1_String(t *testing.T) {
+	t.Run("should summarize blueprint", func(t *testing.T) {
+		sut := BlueprintV1{
+			GeneralBlueprint: GeneralBlueprint{API: V1},
+			ID:               "my-blueprint",
+			CesAppVersion:    "7.0.0-1",
+			Dogus: []TargetDogu{
+				{Name: "official/nginx", Version: "1.2.3-4"},
+				{Name: "official/scm", Version: "2.0.0-1"},
+				{Name: "official/redmine", TargetState: TargetStateAbsent},
+			},
+			Packages:                []TargetPackage{{Name: "cesapp", Version: "7.0.0-1"}},
+			RegistryConfig:          RegistryConfig{"_global": {"fqdn": "ces.example.com", "admin_group": "admins"}, "redmine": {"theme": "dark"}},
+			RegistryConfigAbsent:    []string{"redmine/theme"},
+			RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+		}
+
+		actual := sut.String()
+
+		assert.Equal(t, `blueprint "my-blueprint" (API v1, cesapp 7.0.0-1): dogus 3 (present: 2, absent: 1), packages 1 (present: 1), `+
+			`registry config keys: 3, absent registry config keys: 1, encrypted registry config keys: 1`, actual)
+		assert.Equal(t, actual, fmt.Sprintf("%v", sut))
+	})
+	t.Run("should summarize empty blueprint", func(t *testing.T) {
+		actual := BlueprintV1{}.String()
+
+		assert.Equal(t, `blueprint "" (API , cesapp ): dogus 0, packages 0, registry config keys: 0, absent registry config keys: 0, encrypted registry config keys: 0`, actual)
+	})
+	t.Run("should show undefined target states", func(t *testing.T) {
+		sut := BlueprintV1{Dogus: []TargetDogu{{Name: "official/nginx", TargetState: TargetState(99)}}}
+
+		assert.Contains(t, sut.String(), "dogus 1 (99: 1)")
+	})
+}
+
+func TestBlueprintV1_DoguNamespaces(t *testing.T) {
+	t.Run("should return sorted unique namespaces", func(t *testing.T) {
+		sut := BlueprintV1{Dogus: []TargetDogu{
+			{Name: "premium/backup"},
+			{Name: "official/nginx"},
+			{Name: "k8s/nginx-ingress"},
+			{Name: "official/redmine", TargetState: TargetStateAbsent},
+		}}
+
+		actual, err := sut.DoguNamespaces()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"k8s", "official", "premium"}, actual)
+	})
+	t.Run("should return nil for blueprint without dogus", func(t *testing.T) {
+		actual, err := BlueprintV1{}.DoguNamespaces()
+
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+	t.Run("should fail for malformed name", func(t *testing.T) {
+		sut := BlueprintV1{Dogus: []TargetDogu{{Name: "official/nginx"}, {Name: "redmine"}}}
+
+		actual, err := sut.DoguNamespaces()
+
+		assert.Nil(t, actual)
+		var nameErr *InvalidDoguNameError
+		assert.ErrorAs(t, err, &nameErr)
+		assert.ErrorContains(t, err, "could not determine namespace of dogu at index 1")
+	})
+}
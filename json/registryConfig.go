@@ -0,0 +1,306 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	registryKeySeparator = "/"
+	redactedValue        = "***"
+)
+
+// EncryptedRegistryConfig contains registry config entries which are stored encrypted in the registry. It is
+// marshalled like a RegistryConfig, but its values are redacted when it is formatted as a string, f. i. by a logger,
+// to prevent leaking secrets.
+type EncryptedRegistryConfig map[string]map[string]interface{}
+
+// UnmarshalYAML decodes the encrypted registry config like a RegistryConfig, see RegistryConfig.UnmarshalYAML.
+func (e *EncryptedRegistryConfig) UnmarshalYAML(value *yaml.Node) error {
+	return (*RegistryConfig)(e).UnmarshalYAML(value)
+}
+
+// String returns a representation of the encrypted registry config in which all values are redacted.
+func (e EncryptedRegistryConfig) String() string {
+	return fmt.Sprint(e.redacted())
+}
+
+// GoString returns a Go-syntax representation of the encrypted registry config in which all values are redacted. It
+// is used when the config is formatted with the %#v verb.
+func (e EncryptedRegistryConfig) GoString() string {
+	return fmt.Sprintf("%#v", e.redacted())
+}
+
+func (e EncryptedRegistryConfig) redacted() map[string]map[string]string {
+	if e == nil {
+		return nil
+	}
+
+	result := make(map[string]map[string]string, len(e))
+	for section, entries := range e {
+		result[section] = make(map[string]string, len(entries))
+		for key := range entries {
+			result[section][key] = redactedValue
+		}
+	}
+	return result
+}
+
+// DeepCopy returns a copy of the encrypted registry config that shares no maps or slices with the original.
+func (e EncryptedRegistryConfig) DeepCopy() EncryptedRegistryConfig {
+	return EncryptedRegistryConfig(RegistryConfig(e).DeepCopy())
+}
+
+// Flatten converts the two-level registry config into a single-level map whose keys are the key paths in the form
+// "section/key", f. i. "_global/fqdn". The values are not copied.
+func (r RegistryConfig) Flatten() map[string]interface{} {
+	result := map[string]interface{}{}
+	for section, entries := range r {
+		for key, value := range entries {
+			result[section+registryKeySeparator+key] = value
+		}
+	}
+	return result
+}
+
+// Unflatten is the inverse of RegistryConfig.Flatten. Each key path is split at its first separator into the section
+// and the key, so that keys may contain further separators. An error is returned if a key path does not contain a
+// separator or if its section or key is empty.
+func Unflatten(flat map[string]interface{}) (RegistryConfig, error) {
+	result := RegistryConfig{}
+	for keyPath, value := range flat {
+		section, key, found := strings.Cut(keyPath, registryKeySeparator)
+		if !found || section == "" || key == "" {
+			return nil, fmt.Errorf("registry key path %q must consist of a section and a key separated by %q", keyPath, registryKeySeparator)
+		}
+
+		if result[section] == nil {
+			result[section] = map[string]interface{}{}
+		}
+		result[section][key] = value
+	}
+	return result, nil
+}
+
+// Get returns the value of the given key in the given section. The second return value is false if the section or the
+// key does not exist.
+func (r RegistryConfig) Get(section, key string) (interface{}, bool) {
+	value, ok := r[section][key]
+	return value, ok
+}
+
+// GetString returns the string value of the given key in the given section. The second return value is false if the
+// section or the key does not exist or if the value is not a string.
+func (r RegistryConfig) GetString(section, key string) (string, bool) {
+	value, ok := r.Get(section, key)
+	if !ok {
+		return "", false
+	}
+
+	str, ok := value.(string)
+	return str, ok
+}
+
+// Merge returns a new registry config containing the entries of both registry configs. Sections contained in both
+// configs are merged key by key. If a key is contained in both configs, the value of other takes precedence. Values
+// are replaced as a whole, so nested JSON objects in values are not merged. Neither config is modified and the result
+// shares no maps or slices with them.
+func (r RegistryConfig) Merge(other RegistryConfig) RegistryConfig {
+	if r == nil && other == nil {
+		return nil
+	}
+
+	result := r.DeepCopy()
+	if result == nil {
+		result = RegistryConfig{}
+	}
+	for section, entries := range other.DeepCopy() {
+		if result[section] == nil {
+			result[section] = entries
+			continue
+		}
+		for key, value := range entries {
+			result[section][key] = value
+		}
+	}
+	return result
+}
+
+// RegistryValueType is the JSON type of a registry config value.
+type RegistryValueType string
+
+const (
+	RegistryValueString  RegistryValueType = "string"
+	RegistryValueNumber  RegistryValueType = "number"
+	RegistryValueBoolean RegistryValueType = "boolean"
+	RegistryValueObject  RegistryValueType = "object"
+	RegistryValueArray   RegistryValueType = "array"
+	RegistryValueNull    RegistryValueType = "null"
+)
+
+// ValidateValueTypes checks that every value of the registry config has one of the allowed JSON types. If no types
+// are given, only strings are allowed because this is what the registry stores. Values of allowed objects and arrays
+// are checked recursively. All violations are aggregated into the returned error, each naming the key path of the
+// offending value, f. i. "redmine/settings/theme" or "redmine/plugins[0]".
+func (r RegistryConfig) ValidateValueTypes(allowed ...RegistryValueType) error {
+	if len(allowed) == 0 {
+		allowed = []RegistryValueType{RegistryValueString}
+	}
+
+	var errs []error
+	flat := r.Flatten()
+	for _, keyPath := range unionOfKeys(flat, nil) {
+		errs = append(errs, validateValueType(keyPath, flat[keyPath], allowed)...)
+	}
+	return errors.Join(errs...)
+}
+
+func validateValueType(keyPath string, value interface{}, allowed []RegistryValueType) []error {
+	valueType, ok := registryValueTypeOf(value)
+	if !ok {
+		return []error{fmt.Errorf("registry config value at %q has unsupported type %T", keyPath, value)}
+	}
+	if !containsValueType(allowed, valueType) {
+		return []error{fmt.Errorf("registry config value at %q has type %s, allowed types are %s", keyPath, valueType, joinValueTypes(allowed))}
+	}
+
+	var errs []error
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for _, key := range unionOfKeys(typedValue, nil) {
+			errs = append(errs, validateValueType(keyPath+registryKeySeparator+key, typedValue[key], allowed)...)
+		}
+	case []interface{}:
+		for i, nestedValue := range typedValue {
+			errs = append(errs, validateValueType(fmt.Sprintf("%s[%d]", keyPath, i), nestedValue, allowed)...)
+		}
+	}
+	return errs
+}
+
+func registryValueTypeOf(value interface{}) (RegistryValueType, bool) {
+	switch value.(type) {
+	case nil:
+		return RegistryValueNull, true
+	case string:
+		return RegistryValueString, true
+	case bool:
+		return RegistryValueBoolean, true
+	case map[string]interface{}:
+		return RegistryValueObject, true
+	case []interface{}:
+		return RegistryValueArray, true
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return RegistryValueNumber, true
+	default:
+		return "", false
+	}
+}
+
+func containsValueType(valueTypes []RegistryValueType, valueType RegistryValueType) bool {
+	for _, candidate := range valueTypes {
+		if candidate == valueType {
+			return true
+		}
+	}
+	return false
+}
+
+func joinValueTypes(valueTypes []RegistryValueType) string {
+	names := make([]string, 0, len(valueTypes))
+	for _, valueType := range valueTypes {
+		names = append(names, string(valueType))
+	}
+	return strings.Join(names, ", ")
+}
+
+// DeepCopy returns a copy of the registry config that shares no maps or slices with the original, including nested
+// JSON objects and arrays in the values.
+func (r RegistryConfig) DeepCopy() RegistryConfig {
+	if r == nil {
+		return nil
+	}
+
+	result := make(RegistryConfig, len(r))
+	for section, entries := range r {
+		if entries == nil {
+			result[section] = nil
+			continue
+		}
+
+		copiedEntries := make(map[string]interface{}, len(entries))
+		for key, value := range entries {
+			copiedEntries[key] = deepCopyValue(value)
+		}
+		result[section] = copiedEntries
+	}
+	return result
+}
+
+func deepCopyValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typedValue))
+		for key, nestedValue := range typedValue {
+			result[key] = deepCopyValue(nestedValue)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, nestedValue := range typedValue {
+			result[i] = deepCopyValue(nestedValue)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// UnmarshalYAML decodes the registry config like the default yaml decoding, but converts integers to float64 like
+// encoding/json does, so that a blueprint read from YAML equals the same blueprint read from JSON.
+func (r *RegistryConfig) UnmarshalYAML(value *yaml.Node) error {
+	var config map[string]map[string]interface{}
+	err := value.Decode(&config)
+	if err != nil {
+		return err
+	}
+
+	for _, entries := range config {
+		for key, entry := range entries {
+			entries[key] = jsonCompatibleValue(entry)
+		}
+	}
+	*r = config
+	return nil
+}
+
+// jsonCompatibleValue converts the integers in the given decoded yaml value, including those nested in objects and
+// arrays, to float64.
+func jsonCompatibleValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case int:
+		return float64(typed)
+	case int64:
+		return float64(typed)
+	case uint64:
+		return float64(typed)
+	case map[string]interface{}:
+		for key, entry := range typed {
+			typed[key] = jsonCompatibleValue(entry)
+		}
+	case []interface{}:
+		for i, entry := range typed {
+			typed[i] = jsonCompatibleValue(entry)
+		}
+	}
+	return value
+}
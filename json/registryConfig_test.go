@@ -0,0 +1,256 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryConfig_DeepCopy(t *testing.T) {
+	t.Run("should copy nil config", func(t *testing.T) {
+		var sut RegistryConfig
+
+		assert.Nil(t, sut.DeepCopy())
+	})
+	t.Run("should not share nested maps and slices", func(t *testing.T) {
+		sut := RegistryConfig{
+			"_global": {"fqdn": "ces.example.com"},
+			"redmine": {
+				"settings": map[string]interface{}{"theme": "dark"},
+				"plugins":  []interface{}{"a", "b"},
+			},
+		}
+
+		actual := sut.DeepCopy()
+		actual["_global"]["fqdn"] = "changed.example.com"
+		actual["redmine"]["settings"].(map[string]interface{})["theme"] = "light"
+		actual["redmine"]["plugins"].([]interface{})[0] = "c"
+		actual["new"] = map[string]interface{}{}
+
+		assert.Equal(t, RegistryConfig{
+			"_global": {"fqdn": "ces.example.com"},
+			"redmine": {
+				"settings": map[string]interface{}{"theme": "dark"},
+				"plugins":  []interface{}{"a", "b"},
+			},
+		}, sut)
+	})
+}
+
+func TestRegistryConfig_Flatten(t *testing.T) {
+	sut := RegistryConfig{
+		"_global": {"fqdn": "ces.example.com", "admin_group": "admins"},
+		"redmine": {"logging/root": "INFO"},
+		"empty":   {},
+	}
+
+	actual := sut.Flatten()
+
+	assert.Equal(t, map[string]interface{}{
+		"_global/fqdn":         "ces.example.com",
+		"_global/admin_group":  "admins",
+		"redmine/logging/root": "INFO",
+	}, actual)
+}
+
+func TestUnflatten(t *testing.T) {
+	t.Run("should be inverse of flatten", func(t *testing.T) {
+		config := RegistryConfig{
+			"_global": {"fqdn": "ces.example.com", "admin_group": "admins"},
+			"redmine": {"logging/root": "INFO"},
+		}
+
+		actual, err := Unflatten(config.Flatten())
+
+		require.NoError(t, err)
+		assert.Equal(t, config, actual)
+	})
+	t.Run("should fail for malformed key paths", func(t *testing.T) {
+		for _, keyPath := range []string{"fqdn", "/fqdn", "_global/", ""} {
+			_, err := Unflatten(map[string]interface{}{keyPath: "value"})
+
+			assert.ErrorContains(t, err, "must consist of a section and a key", keyPath)
+		}
+	})
+}
+
+func TestEncryptedRegistryConfig_String(t *testing.T) {
+	sut := EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t", "token": "t0k3n"}}
+
+	assert.Equal(t, "map[redmine:map[secret:*** token:***]]", sut.String())
+	assert.NotContains(t, fmt.Sprintf("%v %+v %#v %s", sut, sut, sut, sut), "s3cr3t")
+}
+
+func TestEncryptedRegistryConfig_redactedInBlueprint(t *testing.T) {
+	sut := BlueprintV1{
+		ID:                      "my-blueprint",
+		RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}},
+	}
+
+	formatted := fmt.Sprintf("%v %+v %#v %s", sut, sut, sut, sut)
+
+	assert.NotContains(t, formatted, "s3cr3t")
+	assert.Contains(t, fmt.Sprintf("%#v", sut), `"secret":"***"`)
+}
+
+func TestEncryptedRegistryConfig_jsonRoundTrip(t *testing.T) {
+	sut := BlueprintV1{RegistryConfigEncrypted: EncryptedRegistryConfig{"redmine": {"secret": "s3cr3t"}}}
+
+	rawBlueprint, err := json.Marshal(sut)
+	require.NoError(t, err)
+	assert.Contains(t, string(rawBlueprint), `"registryConfigEncrypted":{"redmine":{"secret":"s3cr3t"}}`)
+
+	var actual BlueprintV1
+	require.NoError(t, json.Unmarshal(rawBlueprint, &actual))
+	assert.Equal(t, sut, actual)
+}
+
+func TestRegistryConfig_Get(t *testing.T) {
+	sut := RegistryConfig{"_global": {"fqdn": "ces.example.com", "port": 443.0, "empty": nil}}
+
+	tests := []struct {
+		name      string
+		config    RegistryConfig
+		section   string
+		key       string
+		wantValue interface{}
+		wantOk    bool
+	}{
+		{"existing string", sut, "_global", "fqdn", "ces.example.com", true},
+		{"existing number", sut, "_global", "port", 443.0, true},
+		{"existing null", sut, "_global", "empty", nil, true},
+		{"missing key", sut, "_global", "mail", nil, false},
+		{"missing section", sut, "redmine", "fqdn", nil, false},
+		{"nil config", nil, "_global", "fqdn", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := tt.config.Get(tt.section, tt.key)
+
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
+
+func TestRegistryConfig_GetString(t *testing.T) {
+	sut := RegistryConfig{"_global": {"fqdn": "ces.example.com", "port": 443.0}}
+
+	t.Run("should return string value", func(t *testing.T) {
+		value, ok := sut.GetString("_global", "fqdn")
+
+		assert.True(t, ok)
+		assert.Equal(t, "ces.example.com", value)
+	})
+	t.Run("should return false for value of other type", func(t *testing.T) {
+		value, ok := sut.GetString("_global", "port")
+
+		assert.False(t, ok)
+		assert.Empty(t, value)
+	})
+	t.Run("should return false for missing key", func(t *testing.T) {
+		_, ok := sut.GetString("redmine", "theme")
+
+		assert.False(t, ok)
+	})
+}
+
+func TestRegistryConfig_Merge(t *testing.T) {
+	t.Run("should merge overlapping sections with other taking precedence", func(t *testing.T) {
+		sut := RegistryConfig{
+			"_global": {"fqdn": "base.example.com", "admin_group": "admins"},
+			"redmine": {"settings": map[string]interface{}{"theme": "dark", "lang": "de"}},
+		}
+		other := RegistryConfig{
+			"_global": {"fqdn": "other.example.com"},
+			"redmine": {"settings": map[string]interface{}{"theme": "light"}},
+		}
+
+		actual := sut.Merge(other)
+
+		assert.Equal(t, RegistryConfig{
+			"_global": {"fqdn": "other.example.com", "admin_group": "admins"},
+			"redmine": {"settings": map[string]interface{}{"theme": "light"}},
+		}, actual)
+	})
+	t.Run("should merge disjoint sections", func(t *testing.T) {
+		sut := RegistryConfig{"_global": {"fqdn": "ces.example.com"}}
+		other := RegistryConfig{"redmine": {"theme": "dark"}}
+
+		actual := sut.Merge(other)
+
+		assert.Equal(t, RegistryConfig{"_global": {"fqdn": "ces.example.com"}, "redmine": {"theme": "dark"}}, actual)
+	})
+	t.Run("should not modify inputs", func(t *testing.T) {
+		sut := RegistryConfig{"_global": {"fqdn": "base.example.com"}}
+		other := RegistryConfig{"_global": {"mail": "admin@example.com"}, "redmine": {"theme": "dark"}}
+
+		actual := sut.Merge(other)
+		actual["_global"]["fqdn"] = "changed"
+		actual["redmine"]["theme"] = "changed"
+
+		assert.Equal(t, RegistryConfig{"_global": {"fqdn": "base.example.com"}}, sut)
+		assert.Equal(t, RegistryConfig{"_global": {"mail": "admin@example.com"}, "redmine": {"theme": "dark"}}, other)
+	})
+	t.Run("should handle nil configs", func(t *testing.T) {
+		var empty RegistryConfig
+		config := RegistryConfig{"_global": {"fqdn": "ces.example.com"}}
+
+		assert.Nil(t, empty.Merge(nil))
+		assert.Equal(t, config, empty.Merge(config))
+		assert.Equal(t, config, config.Merge(nil))
+	})
+}
+
+func TestRegistryConfig_ValidateValueTypes(t *testing.T) {
+	t.Run("should allow only strings by default", func(t *testing.T) {
+		sut := RegistryConfig{
+			"_global": {"fqdn": "ces.example.com", "pretty": true},
+			"redmine": {"port": 8080, "settings": map[string]interface{}{"theme": "dark"}},
+		}
+
+		err := sut.ValidateValueTypes()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `registry config value at "_global/pretty" has type boolean, allowed types are string`)
+		assert.ErrorContains(t, err, `registry config value at "redmine/port" has type number, allowed types are string`)
+		assert.ErrorContains(t, err, `registry config value at "redmine/settings" has type object, allowed types are string`)
+		assert.NotContains(t, err.Error(), "_global/fqdn")
+	})
+	t.Run("should succeed for strings only", func(t *testing.T) {
+		sut := RegistryConfig{"_global": {"fqdn": "ces.example.com"}}
+
+		assert.NoError(t, sut.ValidateValueTypes())
+	})
+	t.Run("should check nested values of allowed objects and arrays", func(t *testing.T) {
+		sut := RegistryConfig{"redmine": {
+			"settings": map[string]interface{}{"theme": "dark", "limit": 3.0},
+			"plugins":  []interface{}{"agile", nil},
+		}}
+
+		err := sut.ValidateValueTypes(RegistryValueString, RegistryValueObject, RegistryValueArray)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `registry config value at "redmine/settings/limit" has type number, allowed types are string, object, array`)
+		assert.ErrorContains(t, err, `registry config value at "redmine/plugins[1]" has type null`)
+	})
+	t.Run("should detect the types of parsed json", func(t *testing.T) {
+		var sut RegistryConfig
+		require.NoError(t, json.Unmarshal([]byte(`{"a": {"s": "x", "n": 1, "b": false, "o": {}, "l": [], "z": null}}`), &sut))
+
+		err := sut.ValidateValueTypes(RegistryValueString, RegistryValueNumber, RegistryValueBoolean,
+			RegistryValueObject, RegistryValueArray, RegistryValueNull)
+
+		assert.NoError(t, err)
+	})
+	t.Run("should fail for unsupported go types", func(t *testing.T) {
+		sut := RegistryConfig{"a": {"key": struct{}{}}}
+
+		err := sut.ValidateValueTypes()
+
+		assert.ErrorContains(t, err, `registry config value at "a/key" has unsupported type struct {}`)
+	})
+}
@@ -0,0 +1,88 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const doguNameSeparator = "/"
+
+// Validate checks that the dogu has a name including its namespace, f. i. "official/nginx", and that it has a version
+// if it is supposed to be present. All found violations are aggregated into the returned error.
+func (d TargetDogu) Validate() error {
+	return validationError(d.validationIssues())
+}
+
+// validationIssues returns the issues of the dogu with paths relative to the dogu, see BlueprintV1.ValidationReport.
+func (d TargetDogu) validationIssues() []ValidationIssue {
+	issues := itemIssues(d.Name, d.Version, d.TargetState, validateVersion)
+	if d.Name != "" {
+		_, _, err := d.SplitName()
+		if err != nil {
+			issues = append(issues, errorIssue(IssueCodeInvalidName, "name", err))
+		}
+	}
+	return issues
+}
+
+// InvalidDoguNameError is returned if a dogu name does not consist of exactly one namespace and one simple name, f. i.
+// "official/nginx".
+type InvalidDoguNameError struct {
+	// Name contains the invalid dogu name.
+	Name string
+	// Reason describes why the dogu name is invalid.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *InvalidDoguNameError) Error() string {
+	return fmt.Sprintf("dogu name %q %s", e.Name, e.Reason)
+}
+
+// SplitName splits the name of the dogu into its namespace and its simple name, f. i. "official/nginx" is split into
+// "official" and "nginx". An *InvalidDoguNameError is returned if the name does not contain exactly one separator or
+// any of the two parts is empty.
+func (d TargetDogu) SplitName() (namespace string, simpleName string, err error) {
+	parts := strings.Split(d.Name, doguNameSeparator)
+	if len(parts) != 2 {
+		return "", "", &InvalidDoguNameError{
+			Name:   d.Name,
+			Reason: fmt.Sprintf("must consist of a namespace and a name separated by exactly one %q", doguNameSeparator),
+		}
+	}
+	if parts[0] == "" {
+		return "", "", &InvalidDoguNameError{Name: d.Name, Reason: "must not have an empty namespace"}
+	}
+	if parts[1] == "" {
+		return "", "", &InvalidDoguNameError{Name: d.Name, Reason: "must not have an empty simple name"}
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// Equal returns true if both dogus have the same name, version and target state. Comments are ignored.
+func (d TargetDogu) Equal(other TargetDogu) bool {
+	return d.Name == other.Name && d.Version == other.Version && d.TargetState == other.TargetState
+}
+
+// MarshalJSON marshals the dogu like the default marshalling, but omits the target state if it is the default
+// TargetStatePresent.
+func (d TargetDogu) MarshalJSON() ([]byte, error) {
+	// the alias prevents an endless recursion, the shallower TargetState field takes precedence over the embedded one
+	type targetDoguAlias TargetDogu
+	return json.Marshal(struct {
+		targetDoguAlias
+		TargetState *TargetState `json:"targetState,omitempty"`
+	}{
+		targetDoguAlias: targetDoguAlias(d),
+		TargetState:     nonDefaultTargetState(d.TargetState),
+	})
+}
+
+func nonDefaultTargetState(state TargetState) *TargetState {
+	if state == TargetStatePresent {
+		return nil
+	}
+	return &state
+}
@@ -0,0 +1,172 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetDogu_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dogu    TargetDogu
+		wantErr string
+	}{
+		{
+			"valid namespaced name",
+			TargetDogu{Name: "official/nginx", Version: "1.2.3-4"},
+			"",
+		},
+		{
+			"missing namespace",
+			TargetDogu{Name: "nginx", Version: "1.2.3-4"},
+			`dogu name "nginx" must consist of a namespace and a name separated by exactly one "/"`,
+		},
+		{
+			"empty namespace",
+			TargetDogu{Name: "/nginx", Version: "1.2.3-4"},
+			`dogu name "/nginx" must not have an empty namespace`,
+		},
+		{
+			"missing name",
+			TargetDogu{Name: "official/", Version: "1.2.3-4"},
+			`dogu name "official/" must not have an empty simple name`,
+		},
+		{
+			"too many segments",
+			TargetDogu{Name: "a/b/c", Version: "1.2.3-4"},
+			`dogu name "a/b/c" must consist of a namespace and a name separated by exactly one "/"`,
+		},
+		{
+			"version with nano part",
+			TargetDogu{Name: "official/nginx", Version: "1.2.3.4-5"},
+			`version "1.2.3.4-5" of "official/nginx" is invalid: version must consist of exactly three numeric parts and an optional extension like "1.2.3-4"`,
+		},
+		{
+			"version with two parts",
+			TargetDogu{Name: "official/nginx", Version: "1.2-3"},
+			`version "1.2-3" of "official/nginx" is invalid: version must consist of exactly three numeric parts`,
+		},
+		{
+			"valid version without extension",
+			TargetDogu{Name: "official/nginx", Version: "1.2.3"},
+			"",
+		},
+		{
+			"operator inside version",
+			TargetDogu{Name: "official/nginx", Version: "1.2.3=4"},
+			`version "1.2.3=4" of "official/nginx" is invalid: version must not contain a comparison operator`,
+		},
+		{
+			"more than one hyphen in version",
+			TargetDogu{Name: "official/nginx", Version: "1.2.3-4-5"},
+			`version "1.2.3-4-5" of "official/nginx" is invalid: found more than one hyphen in version`,
+		},
+		{
+			"non-numeric version",
+			TargetDogu{Name: "official/nginx", Version: "latest"},
+			`version "latest" of "official/nginx" is invalid: failed to parse major version`,
+		},
+		{
+			"trailing whitespace in version",
+			TargetDogu{Name: "official/nginx", Version: "1.2.3-4 "},
+			`version "1.2.3-4 " of "official/nginx" is invalid: version must not contain surrounding whitespace`,
+		},
+		{
+			"operator in version",
+			TargetDogu{Name: "official/nginx", Version: ">=1.2.3-4"},
+			`version ">=1.2.3-4" of "official/nginx" is invalid: version must not contain a comparison operator`,
+		},
+		{
+			"empty version for absent dogu",
+			TargetDogu{Name: "official/nginx", TargetState: TargetStateAbsent},
+			"",
+		},
+		{
+			"empty name",
+			TargetDogu{Name: "", TargetState: TargetStateAbsent},
+			"name must not be empty",
+		},
+		{
+			"missing version for present dogu",
+			TargetDogu{Name: "official/nginx", TargetState: TargetStatePresent},
+			`version of "official/nginx" must not be empty if the target state is present`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dogu.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTargetDogu_SplitName(t *testing.T) {
+	t.Run("should split namespaced name", func(t *testing.T) {
+		sut := TargetDogu{Name: "official/nginx"}
+
+		namespace, simpleName, err := sut.SplitName()
+
+		require.NoError(t, err)
+		assert.Equal(t, "official", namespace)
+		assert.Equal(t, "nginx", simpleName)
+	})
+	t.Run("should return typed error for malformed names", func(t *testing.T) {
+		for _, name := range []string{"", "nginx", "/nginx", "official/", "a/b/c"} {
+			sut := TargetDogu{Name: name}
+
+			namespace, simpleName, err := sut.SplitName()
+
+			var nameErr *InvalidDoguNameError
+			require.ErrorAs(t, err, &nameErr, name)
+			assert.Equal(t, name, nameErr.Name)
+			assert.Empty(t, namespace)
+			assert.Empty(t, simpleName)
+		}
+	})
+}
+
+func TestTargetDogu_Equal(t *testing.T) {
+	sut := TargetDogu{Name: "official/nginx", Version: "1.2.3-4"}
+
+	assert.True(t, sut.Equal(TargetDogu{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStatePresent}))
+	assert.False(t, sut.Equal(TargetDogu{Name: "official/nginx", Version: "1.2.3-5"}))
+	assert.False(t, sut.Equal(TargetDogu{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStateAbsent}))
+	assert.False(t, sut.Equal(TargetDogu{Name: "premium/nginx", Version: "1.2.3-4"}))
+	assert.True(t, sut.Equal(TargetDogu{Name: "official/nginx", Version: "1.2.3-4", Comment: "pinned"}))
+}
+
+func TestTargetDogu_MarshalJSON(t *testing.T) {
+	t.Run("should omit default target state", func(t *testing.T) {
+		actual, err := json.Marshal(TargetDogu{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStatePresent})
+
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"official/nginx","version":"1.2.3-4"}`, string(actual))
+	})
+	t.Run("should keep non-default target state", func(t *testing.T) {
+		actual, err := json.Marshal(TargetDogu{Name: "official/nginx", TargetState: TargetStateAbsent})
+
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"official/nginx","version":"","targetState":"absent"}`, string(actual))
+	})
+	t.Run("should round-trip", func(t *testing.T) {
+		for _, sut := range []TargetDogu{
+			{Name: "official/nginx", Version: "1.2.3-4", TargetState: TargetStatePresent},
+			{Name: "official/nginx", TargetState: TargetStateAbsent},
+		} {
+			rawDogu, err := json.Marshal(sut)
+			require.NoError(t, err)
+
+			var actual TargetDogu
+			require.NoError(t, json.Unmarshal(rawDogu, &actual))
+			assert.Equal(t, sut, actual)
+		}
+	})
+}
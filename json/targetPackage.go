@@ -0,0 +1,79 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+// versionConstraintOperators contains the comparison operators which may prefix a package version. Longer operators
+// come first so that f. i. ">=" is not mistaken for ">".
+var versionConstraintOperators = []string{">=", "<=", "==", "=", ">", "<"}
+
+// Validate checks that the package has a name and that it has a valid version if it is supposed to be present. The
+// version may be prefixed with a comparison operator, see TargetPackage.Matches. All found violations are aggregated
+// into the returned error.
+func (p TargetPackage) Validate() error {
+	return validationError(p.validationIssues())
+}
+
+// validationIssues returns the issues of the package with paths relative to the package, see
+// BlueprintV1.ValidationReport.
+func (p TargetPackage) validationIssues() []ValidationIssue {
+	return itemIssues(p.Name, p.Version, p.TargetState, validateVersionConstraint)
+}
+
+// Matches checks whether the given installed version fulfills the version of the package. The version of the package
+// is either an exact version like "1.2.3-4", which only matches the same version, or a constraint with one of the
+// operators =, ==, >, <, >= and <=, f. i. ">=1.2.0". An empty package version matches every installed version. An
+// error is returned if either version can not be parsed.
+func (p TargetPackage) Matches(installed string) (bool, error) {
+	if p.Version != "" {
+		err := validateVersionConstraint(p.Version)
+		if err != nil {
+			return false, fmt.Errorf("invalid version %q of package %q: %w", p.Version, p.Name, err)
+		}
+	}
+	comparator, err := core.ParseVersionComparator(p.Version)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q of package %q: %w", p.Version, p.Name, err)
+	}
+
+	installedVersion, err := core.ParseVersion(installed)
+	if err != nil {
+		return false, fmt.Errorf("invalid installed version %q of package %q: %w", installed, p.Name, err)
+	}
+
+	return comparator.Allows(installedVersion)
+}
+
+// MarshalJSON marshals the package like the default marshalling, but omits the target state if it is the default
+// TargetStatePresent.
+func (p TargetPackage) MarshalJSON() ([]byte, error) {
+	// the alias prevents an endless recursion, the shallower TargetState field takes precedence over the embedded one
+	type targetPackageAlias TargetPackage
+	return json.Marshal(struct {
+		targetPackageAlias
+		TargetState *TargetState `json:"targetState,omitempty"`
+	}{
+		targetPackageAlias: targetPackageAlias(p),
+		TargetState:        nonDefaultTargetState(p.TargetState),
+	})
+}
+
+// Equal returns true if both packages have the same name, version, target state and package manager. Comments are
+// ignored.
+func (p TargetPackage) Equal(other TargetPackage) bool {
+	return p.Name == other.Name && p.Version == other.Version && p.TargetState == other.TargetState &&
+		p.PackageManager == other.PackageManager
+}
+
+// identity returns the string which identifies the package within a blueprint. Packages without a package manager are
+// identified by their name, all others by their package manager and name, f. i. "apt:curl".
+func (p TargetPackage) identity() string {
+	if p.PackageManager == "" {
+		return p.Name
+	}
+	return p.PackageManager + ":" + p.Name
+}
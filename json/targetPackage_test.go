@@ -0,0 +1,220 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetPackage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkg     TargetPackage
+		wantErr string
+	}{
+		{
+			"valid package",
+			TargetPackage{Name: "cesapp", Version: "7.0.0-1"},
+			"",
+		},
+		{
+			"empty version for absent package",
+			TargetPackage{Name: "cesapp", TargetState: TargetStateAbsent},
+			"",
+		},
+		{
+			"empty name",
+			TargetPackage{Version: "7.0.0-1"},
+			"name must not be empty",
+		},
+		{
+			"missing version for present package",
+			TargetPackage{Name: "cesapp"},
+			`version of "cesapp" must not be empty if the target state is present`,
+		},
+		{
+			"version constraint",
+			TargetPackage{Name: "cesapp", Version: ">=7.0.0-1"},
+			"",
+		},
+		{
+			"malformed version constraint",
+			TargetPackage{Name: "cesapp", Version: "=>7.0.0-1"},
+			`version "=>7.0.0-1" of "cesapp" is invalid`,
+		},
+		{
+			"operator without version",
+			TargetPackage{Name: "cesapp", Version: ">="},
+			`version ">=" of "cesapp" is invalid: version must not be empty`,
+		},
+		{
+			"version constraint with nano part",
+			TargetPackage{Name: "cesapp", Version: ">=7.0.0.1-2"},
+			`version ">=7.0.0.1-2" of "cesapp" is invalid: version must consist of exactly three numeric parts`,
+		},
+		{
+			"version constraint with two operators",
+			TargetPackage{Name: "cesapp", Version: ">=7.0.0<8.0.0"},
+			`version ">=7.0.0<8.0.0" of "cesapp" is invalid: version must not contain a comparison operator`,
+		},
+		{
+			"malformed version",
+			TargetPackage{Name: "cesapp", Version: "7.0.0-1-2"},
+			`version "7.0.0-1-2" of "cesapp" is invalid`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pkg.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTargetPackage_Equal(t *testing.T) {
+	sut := TargetPackage{Name: "cesapp", Version: "7.0.0-1"}
+
+	assert.True(t, sut.Equal(TargetPackage{Name: "cesapp", Version: "7.0.0-1", TargetState: TargetStatePresent}))
+	assert.False(t, sut.Equal(TargetPackage{Name: "cesapp", Version: "7.1.0-1"}))
+	assert.False(t, sut.Equal(TargetPackage{Name: "cesapp", Version: "7.0.0-1", TargetState: TargetStateAbsent}))
+	assert.True(t, sut.Equal(TargetPackage{Name: "cesapp", Version: "7.0.0-1", Comment: "latest"}))
+}
+
+func TestTargetPackage_Matches(t *testing.T) {
+	tests := []struct {
+		version   string
+		installed string
+		want      bool
+	}{
+		{"7.0.0-1", "7.0.0-1", true},
+		{"7.0.0-1", "7.0.1-1", false},
+		{"=7.0.0-1", "7.0.0-1", true},
+		{"==7.0.0-1", "7.0.0-2", false},
+		{">=1.2.0", "1.2.0", true},
+		{">=1.2.0", "1.10.0-3", true},
+		{">=1.2.0", "1.1.9", false},
+		{">1.2.0", "1.2.0", false},
+		{"<2.0.0", "1.9.9-9", true},
+		{"<=2.0.0", "2.0.1", false},
+		{"", "1.0.0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" matches "+tt.installed, func(t *testing.T) {
+			sut := TargetPackage{Name: "cesapp", Version: tt.version}
+
+			actual, err := sut.Matches(tt.installed)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+	t.Run("should fail for invalid constraint", func(t *testing.T) {
+		sut := TargetPackage{Name: "cesapp", Version: "=>1.2.0"}
+
+		_, err := sut.Matches("1.2.0")
+
+		assert.ErrorContains(t, err, `invalid version "=>1.2.0" of package "cesapp"`)
+	})
+	t.Run("should fail for invalid installed version", func(t *testing.T) {
+		sut := TargetPackage{Name: "cesapp", Version: ">=1.2.0"}
+
+		_, err := sut.Matches("latest")
+
+		assert.ErrorContains(t, err, `invalid installed version "latest" of package "cesapp"`)
+	})
+}
+
+func TestTargetPackage_MarshalJSON(t *testing.T) {
+	t.Run("should omit default target state and round-trip", func(t *testing.T) {
+		sut := TargetPackage{Name: "cesapp", Version: "7.0.0-1"}
+
+		rawPackage, err := json.Marshal(sut)
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"cesapp","version":"7.0.0-1"}`, string(rawPackage))
+
+		var actual TargetPackage
+		require.NoError(t, json.Unmarshal(rawPackage, &actual))
+		assert.Equal(t, sut, actual)
+		assert.Equal(t, TargetStatePresent, actual.TargetState)
+	})
+	t.Run("should keep non-default target state", func(t *testing.T) {
+		actual, err := json.Marshal(TargetPackage{Name: "cesapp", TargetState: TargetStateAbsent})
+
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"cesapp","version":"","targetState":"absent"}`, string(actual))
+	})
+	t.Run("should fail for undefined target state", func(t *testing.T) {
+		_, err := json.Marshal(TargetPackage{Name: "cesapp", TargetState: TargetState(99)})
+
+		assert.ErrorContains(t, err, "cannot marshal TargetState 99")
+	})
+}
+
+func TestTargetPackage_packageManager(t *testing.T) {
+	t.Run("should marshal package manager if set", func(t *testing.T) {
+		actual, err := json.Marshal([]TargetPackage{{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"}, {Name: "cesapp", Version: "7.0.0-1"}})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name": "curl", "version": "7.0.0-1", "packageManager": "apt"}, {"name": "cesapp", "version": "7.0.0-1"}]`, string(actual))
+	})
+	t.Run("should allow the same package for different package managers", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Packages = []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+			{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+			{Name: "wget", TargetState: TargetStateAbsent},
+		}
+
+		assert.NoError(t, sut.Validate())
+	})
+	t.Run("should check package without package manager against every package manager", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Packages = []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+			{Name: "curl", Version: "7.1.0-1", PackageManager: "yum"},
+			{Name: "curl", TargetState: TargetStateAbsent},
+		}
+
+		err := sut.Validate()
+
+		assert.ErrorContains(t, err, `package "apt:curl" must not be contained more than once (index 0, 2)`)
+		assert.ErrorContains(t, err, `package "apt:curl" must not be both present (index 0) and absent (index 2)`)
+		assert.ErrorContains(t, err, `package "yum:curl" must not be contained more than once (index 1, 2)`)
+		assert.ErrorContains(t, err, `package "yum:curl" must not be both present (index 1) and absent (index 2)`)
+	})
+	t.Run("should detect duplicates without package manager", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Packages = []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1"},
+			{Name: "curl", Version: "7.1.0-1"},
+		}
+
+		err := sut.Validate()
+
+		assert.ErrorContains(t, err, `package "curl" must not be contained more than once (index 0, 1)`)
+	})
+	t.Run("should detect duplicates of the same package manager", func(t *testing.T) {
+		sut := createValidBlueprint()
+		sut.Packages = []TargetPackage{
+			{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"},
+			{Name: "curl", TargetState: TargetStateAbsent, PackageManager: "apt"},
+		}
+
+		err := sut.Validate()
+
+		assert.ErrorContains(t, err, `package "apt:curl" must not be contained more than once (index 0, 1)`)
+		assert.ErrorContains(t, err, `package "apt:curl" must not be both present (index 0) and absent (index 1)`)
+	})
+	t.Run("should compare package manager", func(t *testing.T) {
+		sut := TargetPackage{Name: "curl", Version: "7.0.0-1", PackageManager: "apt"}
+
+		assert.False(t, sut.Equal(TargetPackage{Name: "curl", Version: "7.0.0-1", PackageManager: "yum"}))
+	})
+}